@@ -5,16 +5,25 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/bierlingm/beats_viewer/pkg/bead"
+	"github.com/bierlingm/beats_viewer/pkg/capture"
+	"github.com/bierlingm/beats_viewer/pkg/chain"
 	"github.com/bierlingm/beats_viewer/pkg/cluster"
+	"github.com/bierlingm/beats_viewer/pkg/config"
+	"github.com/bierlingm/beats_viewer/pkg/entity"
 	"github.com/bierlingm/beats_viewer/pkg/loader"
 	"github.com/bierlingm/beats_viewer/pkg/model"
 	"github.com/bierlingm/beats_viewer/pkg/ripeness"
+	"github.com/bierlingm/beats_viewer/pkg/taxonomy"
 	"github.com/bierlingm/beats_viewer/pkg/timeline"
 	"github.com/bierlingm/beats_viewer/pkg/ui"
+	"github.com/bierlingm/beats_viewer/pkg/ui/components"
 	"github.com/bierlingm/beats_viewer/pkg/ui/views"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -49,21 +58,105 @@ func main() {
 		case "--robot-taxonomy-stats":
 			robotTaxonomyStats()
 			return
+		case "--robot-taxonomy-eval":
+			robotTaxonomyEval()
+			return
+		case "--robot-taxonomy-queue":
+			robotTaxonomyQueue()
+			return
+		case "--robot-reclassify":
+			if len(os.Args) < 3 {
+				fatal("--robot-reclassify requires a beat ID")
+			}
+			robotReclassify(os.Args[2])
+			return
+		case "--robot-cluster-to-bead":
+			if len(os.Args) < 3 {
+				fatal("--robot-cluster-to-bead requires a cluster ID")
+			}
+			robotClusterToBead(os.Args[2])
+			return
+		case "--robot-chain-to-bead":
+			if len(os.Args) < 3 {
+				fatal("--robot-chain-to-bead requires a chain ID")
+			}
+			robotChainToBead(os.Args[2])
+			return
+		case "--robot-beads":
+			robotBeads()
+			return
+		case "--robot-bead":
+			if len(os.Args) < 3 {
+				fatal("--robot-bead requires a beat ID")
+			}
+			robotBead(os.Args[2])
+			return
 		case "--robot-ripeness":
 			if len(os.Args) < 3 {
 				fatal("--robot-ripeness requires a beat ID")
 			}
 			robotRipeness(os.Args[2])
 			return
+		case "--robot-ripeness-history":
+			if len(os.Args) < 3 {
+				fatal("--robot-ripeness-history requires a beat ID")
+			}
+			robotRipenessHistory(os.Args[2])
+			return
+		case "--robot-ripeness-profile":
+			robotRipenessProfile()
+			return
+		case "--robot-calibrate-ripeness":
+			robotCalibrateRipeness()
+			return
 		case "--robot-ripe":
 			robotRipe()
 			return
 		case "--robot-entities":
 			robotEntities()
 			return
+		case "--robot-mentions":
+			if len(os.Args) < 3 {
+				fatal("--robot-mentions requires a beat ID")
+			}
+			robotMentions(os.Args[2])
+			return
+		case "--robot-entity-dictionary":
+			robotEntityDictionary()
+			return
+		case "--robot-entity-merge":
+			if len(os.Args) < 4 {
+				fatal("--robot-entity-merge requires <from> <into>")
+			}
+			robotCurateEntity("merge", os.Args[2], os.Args[3])
+			return
+		case "--robot-entity-retype":
+			if len(os.Args) < 4 {
+				fatal("--robot-entity-retype requires <name> <type>")
+			}
+			robotCurateEntity("retype", os.Args[2], os.Args[3])
+			return
+		case "--robot-entity-promote", "--robot-entity-hide", "--robot-entity-unhide", "--robot-entity-split":
+			if len(os.Args) < 3 {
+				fatal(os.Args[1] + " requires an entity name")
+			}
+			robotCurateEntity(strings.TrimPrefix(os.Args[1], "--robot-entity-"), os.Args[2], "")
+			return
+		case "--robot-entity-graph":
+			robotEntityGraph()
+			return
+		case "--robot-entity-profile":
+			if len(os.Args) < 3 {
+				fatal("--robot-entity-profile requires entity name")
+			}
+			robotEntityProfile(os.Args[2])
+			return
+		case "--robot-entity-trends":
+			robotEntityTrends()
+			return
 		case "--robot-entity-beats":
 			if len(os.Args) < 3 {
-				fatal("--robot-entity-beats requires entity name")
+				fatal("--robot-entity-beats requires entity ID or name")
 			}
 			robotEntityBeats(os.Args[2])
 			return
@@ -103,6 +196,12 @@ func main() {
 		case "--capture":
 			runCapture()
 			return
+		case "serve":
+			runServe()
+			return
+		case "mcp":
+			runMCP()
+			return
 		}
 	}
 
@@ -131,6 +230,9 @@ func printHelp() {
 
 USAGE:
   btv [options]
+  btv serve [--addr host:port] [--root <path>]
+  btv mcp [--root <path>]
+  btv --capture <text>
 
 OPTIONS:
   --root <path>       Root directory for beats discovery (default: current dir)
@@ -144,13 +246,40 @@ ROBOT COMMANDS (JSON output for AI agents):
   --robot-search                Search beats (reads JSON from stdin)
   --robot-show <beat-id>        Show single beat as JSON
   --robot-taxonomy-stats        Channel/source distribution
+  --robot-taxonomy-eval         Cross-validate the classifier on labeled beats (--folds N)
+  --robot-taxonomy-queue        Automatic classifications, least confident first (--limit N)
+  --robot-reclassify <beat-id>  Set a beat's channel/source by hand (--channel, --source, --clear)
+  --robot-bead <beat-id>        Create a bead from a beat with bd and link them (--dry-run)
+  --robot-beads                 Beats with linked beads and their status (--open | --closed)
+  --robot-cluster-to-bead <id>  Create an epic bead from a cluster (--children, --dry-run)
+  --robot-chain-to-bead <id>    Create an epic bead from a chain (--children, --dry-run)
   --robot-ripeness <beat-id>    Get ripeness score breakdown
+  --robot-ripeness-profile      Show the effective ripeness model
+  --robot-ripeness-history <id> Show how a beat's ripeness moved over time
+  --robot-calibrate-ripeness    Fit ripeness weights to outcomes (--dry-run)
   --robot-ripe                  List ripest beats
   --robot-stale                 List stale beats with reasons
   --robot-entities              List all extracted entities
+  --robot-entity-dictionary     Show the effective entity dictionary
+  --robot-mentions <beat-id>    Entity mention spans in a beat
+  --robot-entity-merge <a> <b>  Merge entity a into b (a becomes an alias)
+  --robot-entity-retype <n> <t> Change an entity's type
+  --robot-entity-promote <name> Promote a term to a concept
+  --robot-entity-hide <name>    Hide an entity (--robot-entity-unhide to undo)
+  --robot-entity-split <name>   Undo merges into or from an entity
+  --robot-entity-graph          Entity co-occurrence graph (--format dot, --pmi)
+  --robot-entity-profile <name> Everything the beats say about an entity
+  --robot-entity-trends         Rising and fading entities (--zoom, --window N)
   --robot-timeline              Timeline data by zoom level
   --robot-clusters              List theme clusters
 
+COMMANDS:
+  serve             Serve the robot commands as a local HTTP JSON API
+                    (default 127.0.0.1:7777, OpenAPI at /openapi.json)
+  mcp               Serve beats to AI agents over the Model Context Protocol
+                    on stdin/stdout
+  --capture <text>  Capture a beat with the beats CLI (bt add)
+
 ENVIRONMENT:
   BEATS_ROOT        Override default root directory
 
@@ -174,11 +303,32 @@ func robotHelp() {
 			{Name: "--robot-list", Description: "List beats with filters", Input: "--channel/--source/--sort/--limit flags", Output: "beats array"},
 			{Name: "--robot-search", Description: "Search by content/impetus", Input: `{"query": "...", "max_results": N}`, Output: "results array"},
 			{Name: "--robot-show", Description: "Get beat details", Input: "beat ID", Output: "beat object"},
-			{Name: "--robot-taxonomy-stats", Description: "Channel/source distribution", Output: "channels/sources counts"},
+			{Name: "--robot-taxonomy-stats", Description: "Channel/source distribution over the project's taxonomy", Output: "channels/sources counts, display order, channel colors, manual count and config sources"},
+			{Name: "--robot-taxonomy-eval", Description: "Cross-validated accuracy of keyword, learned and combined classification on beats labeled by hand or impetus.meta", Input: "--folds N (default 5)", Output: "per-facet accuracy and confusion matrix"},
+			{Name: "--robot-taxonomy-queue", Description: "Automatically classified beats to review, least confident first; confirm or fix with --robot-reclassify", Input: "--limit N (default 20, 0 for all)", Output: "beats with suggested channel/source, ranked alternatives and confidence"},
+			{Name: "--robot-reclassify", Description: "Set a beat's channel and/or source by hand; survives rebuilds", Input: "beat ID, --channel name, --source name, --clear", Output: "override and resulting taxonomy"},
+			{Name: "--robot-beads", Description: "Beats linked to beads, with each bead's status, priority and title from the beads project's .beads/ data, and the bead stores found", Input: "--open (beats with an open bead) or --closed (beats whose beads are all closed)", Output: "beats with linked bead statuses and whether they are resolved, plus discovered bead stores"},
+			{Name: "--robot-cluster-to-bead", Description: "Create an epic bead from a cluster with bd, described by its members, keywords and entities, and link every member beat to it; --children also creates a child bead under the epic for each ripe member", Input: "cluster ID, --children, --dry-run", Output: "epic and child bd commands, created epic ID and child beads"},
+			{Name: "--robot-chain-to-bead", Description: "Create an epic bead from a chain, as --robot-cluster-to-bead does for clusters", Input: "chain ID, --children, --dry-run", Output: "epic and child bd commands, created epic ID and child beads"},
+			{Name: "--robot-bead", Description: "Create a bead from a beat with bd and record the link under .beats/; --dry-run only prints the bd command", Input: "beat ID, --dry-run", Output: "bd command, created bead ID and the beat's linked beads"},
 			{Name: "--robot-ripeness", Description: "Get ripeness score+factors", Input: "beat ID", Output: "score breakdown"},
+			{Name: "--robot-ripeness-history", Description: "Ripeness score and lifecycle history", Input: "beat ID", Output: "events array with timestamps"},
+			{Name: "--robot-ripeness-profile", Description: "Show effective ripeness model", Output: "weights, ramps, tiers and config sources"},
+			{Name: "--robot-calibrate-ripeness", Description: "Fit ripeness weights to bead/archive outcomes and save the tuned profile", Input: "--dry-run flag", Output: "tuned profile and factor importance"},
 			{Name: "--robot-ripe", Description: "List ripest beats", Input: "--limit/--threshold flags", Output: "beats sorted by ripeness"},
 			{Name: "--robot-entities", Description: "List all entities", Output: "people/tools/concepts arrays"},
-			{Name: "--robot-entity-beats", Description: "Beats containing entity", Input: "entity name", Output: "beats array"},
+			{Name: "--robot-entity-dictionary", Description: "Show effective entity dictionary", Output: "names by type, aliases, ignores and config sources"},
+			{Name: "--robot-mentions", Description: "Entity mentions with byte offsets", Input: "beat ID", Output: "mentions array with start/end/surface/type"},
+			{Name: "--robot-entity-merge", Description: "Merge duplicate entities", Input: "from name, into name", Output: "curation and entity count"},
+			{Name: "--robot-entity-retype", Description: "Change an entity's type", Input: "name, type (person/tool/concept/project/organization)", Output: "curation and entity count"},
+			{Name: "--robot-entity-promote", Description: "Promote a term to a concept", Input: "name", Output: "curation and entity count"},
+			{Name: "--robot-entity-hide", Description: "Hide an entity from extraction", Input: "name", Output: "curation and entity count"},
+			{Name: "--robot-entity-unhide", Description: "Stop hiding an entity", Input: "name", Output: "curation and entity count"},
+			{Name: "--robot-entity-split", Description: "Undo merges into or from an entity", Input: "name", Output: "restored names"},
+			{Name: "--robot-entity-graph", Description: "Entity co-occurrence graph", Input: "--format json|dot, --pmi, --min-shared N, --entity name|id", Output: "nodes and edges with shared counts and PMI, or Graphviz DOT"},
+			{Name: "--robot-entity-beats", Description: "Beats containing entity", Input: "entity ID (type:name) or name", Output: "beats array"},
+			{Name: "--robot-entity-profile", Description: "Entity profile page", Input: "entity name", Output: "first/last seen, mentions with snippets, channels, sources, related entities, beads, chains and clusters"},
+			{Name: "--robot-entity-trends", Description: "Per-entity time series with trend classification", Input: "--zoom day|week|month|quarter, --window N, --limit N, --entity name", Output: "trends with series, recent/previous counts and rising|fading|steady|new"},
 			{Name: "--robot-timeline", Description: "Timeline bucket data", Input: "--zoom/--start/--end flags", Output: "buckets array"},
 			{Name: "--robot-gaps", Description: "Activity gaps", Input: "--threshold flag", Output: "gaps array"},
 			{Name: "--robot-cluster", Description: "Generate/refresh clusters", Input: "--k flag", Output: "clusters array"},
@@ -212,11 +362,17 @@ func robotList() {
 	if err != nil {
 		fatalJSON("error", err.Error())
 	}
+	d := &dataset{beats: beats, beatToProject: beatToProject}
+	outputJSON(d.list(projectFilter))
+}
 
+// list lists the beats of every project, or of one
+func (d *dataset) list(projectFilter *string) model.RobotListResponse {
+	beats := d.beats
 	if projectFilter != nil {
 		var filtered []model.Beat
 		for _, b := range beats {
-			if beatToProject[b.ID] == *projectFilter {
+			if d.beatToProject[b.ID] == *projectFilter {
 				filtered = append(filtered, b)
 			}
 		}
@@ -225,15 +381,14 @@ func robotList() {
 
 	items := make([]model.BeatListItem, len(beats))
 	for i, b := range beats {
-		items[i] = b.ToListItem(beatToProject[b.ID], 80)
+		items[i] = b.ToListItem(d.beatToProject[b.ID], 80)
 	}
 
-	resp := model.RobotListResponse{
+	return model.RobotListResponse{
 		Beats:         items,
 		Total:         len(items),
 		ProjectFilter: projectFilter,
 	}
-	outputJSON(resp)
 }
 
 func robotSearch() {
@@ -247,33 +402,39 @@ func robotSearch() {
 		fatalJSON("error", "invalid JSON input: "+err.Error())
 	}
 
-	if input.MaxResults == 0 {
-		input.MaxResults = 50
-	}
-
 	rootPath := loader.GetDefaultRoot()
 	beats, beatToProject, err := loader.LoadAllBeats(rootPath)
 	if err != nil {
 		fatalJSON("error", err.Error())
 	}
+	d := &dataset{beats: beats, beatToProject: beatToProject}
+	outputJSON(d.search(input.Query, input.MaxResults))
+}
+
+// search finds beats matching a query across every project, returning at
+// most maxResults (50 when 0)
+func (d *dataset) search(query string, maxResults int) model.RobotSearchResponse {
+	if maxResults == 0 {
+		maxResults = 50
+	}
 
-	results := loader.SearchBeats(beats, input.Query)
+	results := loader.SearchBeats(d.beats, query)
 
-	if len(results) > input.MaxResults {
-		results = results[:input.MaxResults]
+	maxResults = max(maxResults, 0)
+	if len(results) > maxResults {
+		results = results[:maxResults]
 	}
 
 	items := make([]model.BeatListItem, len(results))
 	for i, b := range results {
-		items[i] = b.ToListItem(beatToProject[b.ID], 80)
+		items[i] = b.ToListItem(d.beatToProject[b.ID], 80)
 	}
 
-	resp := model.RobotSearchResponse{
+	return model.RobotSearchResponse{
 		Results:      items,
-		Query:        input.Query,
+		Query:        query,
 		TotalMatches: len(items),
 	}
-	outputJSON(resp)
 }
 
 func robotShow(beatID string) {
@@ -283,14 +444,89 @@ func robotShow(beatID string) {
 		fatalJSON("error", err.Error())
 	}
 
-	beat := loader.FindBeatByID(beats, beatID)
-	if beat == nil {
-		fatalJSON("error", "beat not found: "+beatID)
+	d := &dataset{beats: beats}
+	beat, err := d.show(beatID)
+	if err != nil {
+		fatalJSON("error", err.Error())
 	}
 
 	outputJSON(beat)
 }
 
+// show finds a beat in any project
+func (d *dataset) show(beatID string) (*model.Beat, error) {
+	beat := loader.FindBeatByID(d.beats, beatID)
+	if beat == nil {
+		return nil, errNotFound("beat not found: " + beatID)
+	}
+	return beat, nil
+}
+
+// dataset is what the read-only robot operations work from. Each robot
+// command loads the parts it needs, enriching the first project; btv serve
+// keeps every project in memory, and the enriched views cover them all.
+type dataset struct {
+	beats         []model.Beat // every project's, newest first
+	beatToProject map[string]string
+	projects      []projectData // enriched per project, each with its cache
+}
+
+// projectData is one project's enriched beats with the cache and ripeness
+// profile that scored them
+type projectData struct {
+	enriched []model.EnrichedBeat
+	cache    *model.Cache
+	profile  ripeness.Profile
+}
+
+// enriched returns every project's enriched beats, newest first
+func (d *dataset) enriched() []model.EnrichedBeat {
+	if len(d.projects) == 1 {
+		return d.projects[0].enriched
+	}
+	var all []model.EnrichedBeat
+	for _, pd := range d.projects {
+		all = append(all, pd.enriched...)
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].CreatedAt.After(all[j].CreatedAt)
+	})
+	return all
+}
+
+// loadProjectData enriches a project's beats and loads its ripeness profile
+func loadProjectData(beatsDir string) (projectData, error) {
+	enriched, cache, err := loader.LoadEnrichedBeats(beatsDir, nil)
+	if err != nil {
+		return projectData{}, err
+	}
+	profile, _, err := ripeness.LoadProfile(beatsDir)
+	if err != nil {
+		return projectData{}, err
+	}
+	return projectData{enriched: enriched, cache: cache, profile: profile}, nil
+}
+
+// beatProject returns the beats directory of the project holding a beat
+func beatProject(beatID string) (string, error) {
+	projects, err := loader.DiscoverProjects(loader.GetDefaultRoot())
+	if err != nil || len(projects) == 0 {
+		return "", fmt.Errorf("no projects found")
+	}
+	for _, p := range projects {
+		beats, err := loader.LoadBeats(p.Path)
+		if err == nil && loader.FindBeatByID(beats, beatID) != nil {
+			return p.Path, nil
+		}
+	}
+	return "", errNotFound("beat not found: " + beatID)
+}
+
+// errNotFound reports that something asked for doesn't exist
+type errNotFound string
+
+func (e errNotFound) Error() string { return string(e) }
+
 func outputJSON(v interface{}) {
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
@@ -310,184 +546,1265 @@ func fatalJSON(key, msg string) {
 	os.Exit(1)
 }
 
-func getEnrichedBeats() ([]model.EnrichedBeat, *model.Cache, error) {
+func getBeatsDir() (string, error) {
 	rootPath := loader.GetDefaultRoot()
 	projects, err := loader.DiscoverProjects(rootPath)
 	if err != nil || len(projects) == 0 {
-		return nil, nil, fmt.Errorf("no projects found")
+		return "", fmt.Errorf("no projects found")
+	}
+	return projects[0].Path, nil
+}
+
+func getEnrichedBeats() ([]model.EnrichedBeat, *model.Cache, error) {
+	beatsDir, err := getBeatsDir()
+	if err != nil {
+		return nil, nil, err
+	}
+	return loader.LoadEnrichedBeats(beatsDir, nil)
+}
+
+func getRipenessProfile() ripeness.Profile {
+	beatsDir, err := getBeatsDir()
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	profile, _, err := ripeness.LoadProfile(beatsDir)
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	return profile
+}
+
+func robotTaxonomyStats() {
+	enriched, cache, err := getEnrichedBeats()
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	beatsDir, err := getBeatsDir()
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	def, sources, err := taxonomy.LoadDefinition(beatsDir)
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	if sources == nil {
+		sources = []string{}
+	}
+
+	// Every defined channel and source is listed, even with no beats
+	channels := make(map[string]int)
+	for _, ch := range def.ChannelList() {
+		channels[ch.String()] = 0
+	}
+	sourceCounts := make(map[string]int)
+	for _, src := range def.SourceList() {
+		sourceCounts[src.String()] = 0
+	}
+	// Secondary labels are counted apart from the best ones, so channels and
+	// sources still each sum to the total
+	secondaryChannels := make(map[string]int)
+	secondarySources := make(map[string]int)
+	manual := 0
+
+	for _, eb := range enriched {
+		tax := cache.Taxonomies[eb.ID]
+		channels[tax.Channel.String()]++
+		sourceCounts[tax.Source.String()]++
+		for _, ch := range tax.SecondaryChannels {
+			secondaryChannels[ch.String()]++
+		}
+		for _, src := range tax.SecondarySources {
+			secondarySources[src.String()]++
+		}
+		if tax.Manual {
+			manual++
+		}
+	}
+
+	resp := map[string]interface{}{
+		"channels":           channels,
+		"sources":            sourceCounts,
+		"secondary_channels": secondaryChannels,
+		"secondary_sources":  secondarySources,
+		"label_threshold":    def.LabelThreshold,
+		"channel_order":      def.ChannelList(),
+		"source_order":       def.SourceList(),
+		"colors":             def.ChannelColors(),
+		"manual":             manual,
+		"total":              len(enriched),
+		"config_sources":     sources,
+	}
+	outputJSON(resp)
+}
+
+func robotTaxonomyEval() {
+	folds := 5
+	for i, arg := range os.Args {
+		if arg == "--folds" && i+1 < len(os.Args) {
+			if n, err := strconv.Atoi(os.Args[i+1]); err == nil && n > 1 {
+				folds = n
+			}
+		}
+	}
+
+	beatsDir, err := getBeatsDir()
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	beats, err := loader.LoadBeats(beatsDir)
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	def, _, err := taxonomy.LoadDefinition(beatsDir)
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	overrides, err := loader.LoadTaxonomyOverrides(beatsDir)
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+
+	labeled := def.LabeledBeats(beats, overrides)
+	if len(labeled) < 2 {
+		fatalJSON("error", "need at least 2 labeled beats; set some with --robot-reclassify")
+	}
+	learnChannel, learnSource := def.NewClassifier(labeled).Learned()
+
+	outputJSON(map[string]interface{}{
+		"evaluation": def.Evaluate(labeled, folds),
+		"learning":   def.Learn,
+		"learned": map[string]bool{
+			"channel": learnChannel,
+			"source":  learnSource,
+		},
+	})
+}
+
+func robotReclassify(beatID string) {
+	beatsDir, err := beatProject(beatID)
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+
+	var r reclassification
+	for i, arg := range os.Args {
+		if arg == "--clear" {
+			r.Clear = true
+		}
+		if i+1 >= len(os.Args) {
+			continue
+		}
+		switch arg {
+		case "--channel":
+			r.Channel = os.Args[i+1]
+		case "--source":
+			r.Source = os.Args[i+1]
+		}
+	}
+	if !r.Clear && r.Channel == "" && r.Source == "" {
+		fatalJSON("error", "--robot-reclassify requires --channel, --source or --clear")
+	}
+
+	resp, err := reclassify(beatsDir, beatID, r)
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	outputJSON(resp)
+}
+
+// reclassification is a manual change to a beat's channel and source. Setting
+// one of them keeps an earlier override of the other.
+type reclassification struct {
+	Channel string
+	Source  string
+	Clear   bool // drop the override, going back to the classifier
+}
+
+// reclassify records a manual classification of a beat in the project at
+// beatsDir and returns the beat's resulting taxonomy
+func reclassify(beatsDir, beatID string, r reclassification) (map[string]interface{}, error) {
+	beats, err := loader.LoadBeats(beatsDir)
+	if err != nil {
+		return nil, err
+	}
+	if loader.FindBeatByID(beats, beatID) == nil {
+		return nil, errNotFound("beat not found: " + beatID)
+	}
+
+	overrides, err := loader.LoadTaxonomyOverrides(beatsDir)
+	if err != nil {
+		return nil, err
+	}
+	override := overrides[beatID]
+	switch {
+	case r.Clear:
+		override = model.TaxonomyOverride{}
+	case r.Channel == "" && r.Source == "":
+		return nil, badRequest("a channel, source or clear is required")
+	default:
+		if r.Channel != "" {
+			override.Channel = r.Channel
+		}
+		if r.Source != "" {
+			override.Source = r.Source
+		}
+	}
+	override.At = time.Now()
+
+	if err := loader.SetTaxonomyOverride(beatsDir, beatID, override); err != nil {
+		return nil, err
+	}
+	cache, err := loader.EnsureCache(beatsDir, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	tax := cache.Taxonomies[beatID]
+	resp := map[string]interface{}{
+		"beat_id": beatID,
+		"taxonomy": map[string]interface{}{
+			"channel":    tax.Channel.String(),
+			"source":     tax.Source.String(),
+			"confidence": tax.Confidence,
+			"manual":     tax.Manual,
+			"channels":   labelScores(tax.Channels),
+			"sources":    labelScores(tax.Sources),
+		},
+	}
+	if r.Clear {
+		resp["cleared"] = true
+	}
+	return resp, nil
+}
+
+func robotTaxonomyQueue() {
+	limit := 20
+	for i, arg := range os.Args {
+		if arg == "--limit" && i+1 < len(os.Args) {
+			if n, err := strconv.Atoi(os.Args[i+1]); err == nil {
+				limit = n
+			}
+		}
+	}
+
+	enriched, _, err := getEnrichedBeats()
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+
+	unsure := views.FindUnsureBeats(enriched)
+	total := len(unsure)
+	if limit > 0 && len(unsure) > limit {
+		unsure = unsure[:limit]
+	}
+
+	queue := []map[string]interface{}{}
+	for _, eb := range unsure {
+		queue = append(queue, map[string]interface{}{
+			"id":         eb.ID,
+			"impetus":    eb.ImpetusLabel(),
+			"preview":    eb.ContentPreview(80),
+			"channel":    eb.Taxonomy.Channel.String(),
+			"source":     eb.Taxonomy.Source.String(),
+			"confidence": eb.Taxonomy.Confidence,
+			"channels":   labelScores(eb.Taxonomy.Channels),
+			"sources":    labelScores(eb.Taxonomy.Sources),
+		})
+	}
+	outputJSON(map[string]interface{}{"queue": queue, "count": len(queue), "total": total})
+}
+
+// labelScores returns scores for JSON output, empty rather than null when
+// nothing scored
+func labelScores(scores []model.LabelScore) []model.LabelScore {
+	if scores == nil {
+		return []model.LabelScore{}
+	}
+	return scores
+}
+
+func robotBead(beatID string) {
+	dryRun := false
+	for _, arg := range os.Args {
+		if arg == "--dry-run" {
+			dryRun = true
+		}
+	}
+
+	beatsDir, err := beatProject(beatID)
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	beats, err := loader.LoadBeats(beatsDir)
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	beat := loader.FindBeatByID(beats, beatID)
+	if beat == nil {
+		fatalJSON("error", "beat not found: "+beatID)
+	}
+	cfg, _, err := bead.LoadConfig(beatsDir)
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+
+	args := bead.NewClient(cfg, beatsDir).CreateArgs(bead.IssueForBeat(*beat))
+	resp := map[string]interface{}{
+		"beat_id": beatID,
+		"command": args,
+		"shell":   bead.QuoteArgs(args),
+		"dry_run": dryRun,
+	}
+	if dryRun {
+		outputJSON(resp)
+		return
+	}
+
+	id, err := bead.CreateForBeat(beatsDir, *beat)
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	linked := append([]string(nil), beat.LinkedBeads...)
+	resp["bead_id"] = id
+	resp["linked_beads"] = append(linked, id)
+	outputJSON(resp)
+}
+
+func robotClusterToBead(clusterID string) {
+	beatsDir, g, err := findGroup(func(enriched []model.EnrichedBeat, cache *model.Cache) (bead.Group, bool) {
+		for _, c := range cache.Clusters {
+			if c.ID == clusterID {
+				return bead.ClusterGroup(c, enriched), true
+			}
+		}
+		return bead.Group{}, false
+	})
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	if beatsDir == "" {
+		fatalJSON("error", "cluster not found: "+clusterID)
+	}
+	robotEpic(beatsDir, g)
+}
+
+func robotChainToBead(chainID string) {
+	beatsDir, g, err := findGroup(func(enriched []model.EnrichedBeat, cache *model.Cache) (bead.Group, bool) {
+		for _, c := range cache.Chains {
+			if c.ID == chainID {
+				return bead.ChainGroup(c, enriched), true
+			}
+		}
+		return bead.Group{}, false
+	})
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	if beatsDir == "" {
+		fatalJSON("error", "chain not found: "+chainID)
+	}
+	robotEpic(beatsDir, g)
+}
+
+// findGroup looks through each project's enriched beats in turn for a
+// cluster or chain and returns the first project holding it, with its
+// members from that project. The directory is empty when no project has it.
+func findGroup(find func(enriched []model.EnrichedBeat, cache *model.Cache) (bead.Group, bool)) (string, bead.Group, error) {
+	projects, err := loader.DiscoverProjects(loader.GetDefaultRoot())
+	if err != nil || len(projects) == 0 {
+		return "", bead.Group{}, fmt.Errorf("no projects found")
+	}
+	for _, p := range projects {
+		enriched, cache, err := loader.LoadEnrichedBeats(p.Path, nil)
+		if err != nil {
+			return "", bead.Group{}, err
+		}
+		if g, ok := find(enriched, cache); ok {
+			return p.Path, g, nil
+		}
+	}
+	return "", bead.Group{}, nil
+}
+
+// robotEpic creates, or with --dry-run shows, the epic bead for a cluster or
+// chain
+func robotEpic(beatsDir string, g bead.Group) {
+	dryRun := false
+	cfg, _, err := bead.LoadConfig(beatsDir)
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	children := cfg.EpicChildren
+	for _, arg := range os.Args {
+		switch arg {
+		case "--dry-run":
+			dryRun = true
+		case "--children":
+			children = true
+		}
+	}
+	if len(g.Beats) == 0 {
+		fatalJSON("error", fmt.Sprintf("%s %s has no loaded beats", g.Kind, g.ID))
+	}
+
+	plan := bead.PlanEpic(g, children)
+	client := bead.NewClient(cfg, beatsDir)
+	args := client.CreateArgs(plan.Epic)
+	beatIDs := []string{}
+	for _, eb := range g.Beats {
+		beatIDs = append(beatIDs, eb.ID)
+	}
+	childCommands := []map[string]interface{}{}
+	for _, child := range plan.Children {
+		issue := child.Issue
+		issue.Parent = bead.EpicIDPlaceholder
+		childArgs := client.CreateArgs(issue)
+		childCommands = append(childCommands, map[string]interface{}{
+			"beat_id": child.BeatID,
+			"command": childArgs,
+			"shell":   bead.QuoteArgs(childArgs),
+		})
+	}
+
+	resp := map[string]interface{}{
+		g.Kind + "_id":   g.ID,
+		"name":           g.Name,
+		"beat_ids":       beatIDs,
+		"command":        args,
+		"shell":          bead.QuoteArgs(args),
+		"child_commands": childCommands,
+		"dry_run":        dryRun,
+	}
+	if dryRun {
+		outputJSON(resp)
+		return
+	}
+
+	result, err := bead.CreateEpic(beatsDir, plan, g, nil)
+	if err != nil {
+		if result.EpicID == "" {
+			fatalJSON("error", err.Error())
+		}
+		resp["error"] = err.Error()
+	}
+	resp["epic_id"] = result.EpicID
+	resp["child_beads"] = result.Children
+	outputJSON(resp)
+}
+
+func robotBeads() {
+	filter := components.BeadFilterAll
+	for _, arg := range os.Args {
+		switch arg {
+		case "--open":
+			filter = components.BeadFilterOpen
+		case "--closed":
+			filter = components.BeadFilterClosed
+		}
+	}
+
+	beatsDir, err := getBeatsDir()
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	enriched, _, err := getEnrichedBeats()
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+
+	var ids []string
+	seen := make(map[string]bool)
+	for _, eb := range enriched {
+		for _, id := range eb.LinkedBeads {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	statuses := map[string]model.BeadStatus{}
+	if len(ids) > 0 {
+		if statuses, err = bead.Statuses(beatsDir, ids); err != nil {
+			fatalJSON("error", err.Error())
+		}
+	}
+
+	linked := []map[string]interface{}{}
+	for _, eb := range components.FilterByBeads(enriched, filter, statuses) {
+		if len(eb.LinkedBeads) == 0 {
+			continue
+		}
+		beads := []model.BeadStatus{}
+		for _, id := range eb.LinkedBeads {
+			status, ok := statuses[id]
+			if !ok {
+				status = model.BeadStatus{ID: id}
+			}
+			beads = append(beads, status)
+		}
+		linked = append(linked, map[string]interface{}{
+			"id":       eb.ID,
+			"preview":  eb.ContentPreview(80),
+			"state":    eb.RipenessState,
+			"resolved": eb.BeadsResolved,
+			"beads":    beads,
+		})
+	}
+
+	stores, err := loader.DiscoverBeadStores(loader.GetDefaultRoot())
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	if stores == nil {
+		stores = []model.BeadStore{}
+	}
+
+	outputJSON(map[string]interface{}{
+		"filter": filter.String(),
+		"beats":  linked,
+		"count":  len(linked),
+		"stores": stores,
+	})
+}
+
+func robotRipeness(beatID string) {
+	// Explain the beat with its own project's profile and connections
+	beatsDir, err := beatProject(beatID)
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	pd, err := loadProjectData(beatsDir)
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	d := &dataset{projects: []projectData{pd}}
+	resp, err := d.ripeness(beatID)
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	outputJSON(resp)
+}
+
+// ripeness explains a beat's ripeness score with the profile and connections
+// of the project it belongs to
+func (d *dataset) ripeness(beatID string) (map[string]interface{}, error) {
+	var pd *projectData
+	var target *model.Beat
+	for i := range d.projects {
+		for j := range d.projects[i].enriched {
+			if d.projects[i].enriched[j].ID == beatID {
+				pd, target = &d.projects[i], &d.projects[i].enriched[j].Beat
+				break
+			}
+		}
+		if target != nil {
+			break
+		}
+	}
+	if target == nil {
+		return nil, errNotFound("beat not found: " + beatID)
+	}
+
+	beats := make([]model.Beat, len(pd.enriched))
+	for i, eb := range pd.enriched {
+		beats[i] = eb.Beat
+	}
+	profile := pd.profile
+	cache := pd.cache
+	conns := ripeness.BuildConnections(beats, cache.Entities, cache.EmbeddingNeighbors, profile.SimilarityThreshold)
+	viewStat := cache.ViewStats[beatID]
+	explanation := profile.Explain(*target, conns, viewStat)
+	breakdown := explanation.Breakdown
+
+	return map[string]interface{}{
+		"beat_id": beatID,
+		"score":   breakdown.Total,
+		"tier":    profile.Tiers.Tier(breakdown.Total),
+		"state":   breakdown.State,
+		"profile": profile.Name,
+		"factors": map[string]float64{
+			"age":          breakdown.Age,
+			"revisit":      breakdown.Revisit,
+			"connection":   breakdown.Connection,
+			"action":       breakdown.Action,
+			"completeness": breakdown.Completeness,
+		},
+		"weights":   profile.Weights,
+		"decay":     breakdown.Decay,
+		"idle_days": int(breakdown.IdleDays),
+		"related":   conns[beatID],
+		"reasons":   explanation.Reasons,
+		"boost":     explanation.Boost,
+		"advice":    explanation.Advice,
+		"phrases":   explanation.Phrases,
+	}, nil
+}
+
+func robotRipenessHistory(beatID string) {
+	beatsDir, err := getBeatsDir()
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+
+	enriched, _, err := loader.LoadEnrichedBeats(beatsDir, nil)
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+
+	var target *model.EnrichedBeat
+	for i := range enriched {
+		if enriched[i].ID == beatID {
+			target = &enriched[i]
+			break
+		}
+	}
+	if target == nil {
+		fatalJSON("error", "beat not found: "+beatID)
+	}
+
+	history, err := loader.LoadRipenessHistory(beatsDir)
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+
+	events := history[beatID]
+	if events == nil {
+		events = []model.RipenessEvent{}
+	}
+
+	var transitions []map[string]interface{}
+	for i := 1; i < len(events); i++ {
+		if events[i].State != events[i-1].State {
+			transitions = append(transitions, map[string]interface{}{
+				"from": events[i-1].State,
+				"to":   events[i].State,
+				"at":   events[i].At,
+			})
+		}
+	}
+
+	outputJSON(map[string]interface{}{
+		"beat_id":     beatID,
+		"score":       target.RipenessScore,
+		"state":       target.RipenessState,
+		"events":      events,
+		"transitions": transitions,
+	})
+}
+
+func robotRipenessProfile() {
+	beatsDir, err := getBeatsDir()
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+
+	profile, sources, err := ripeness.LoadProfile(beatsDir)
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	if sources == nil {
+		sources = []string{}
+	}
+
+	outputJSON(map[string]interface{}{
+		"profile":     profile,
+		"fingerprint": profile.Fingerprint(),
+		"sources":     sources,
+		"search_path": config.Paths(beatsDir),
+	})
+}
+
+func robotCalibrateRipeness() {
+	beatsDir, err := getBeatsDir()
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+
+	dryRun := false
+	for _, arg := range os.Args {
+		if arg == "--dry-run" {
+			dryRun = true
+		}
+	}
+
+	enriched, cache, err := loader.LoadEnrichedBeats(beatsDir, nil)
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	decisions, err := loader.LoadReviewDecisions(beatsDir)
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+
+	var beats []model.Beat
+	for _, eb := range enriched {
+		beats = append(beats, eb.Beat)
+	}
+
+	profile := getRipenessProfile()
+	conns := ripeness.BuildConnections(beats, cache.Entities, cache.EmbeddingNeighbors, profile.SimilarityThreshold)
+	samples := profile.BuildSamples(beats, cache.ViewStats, conns, decisions)
+
+	result, err := profile.Calibrate(samples)
+	if err != nil {
+		outputJSON(map[string]interface{}{
+			"error":    err.Error(),
+			"samples":  result.Samples,
+			"by_label": result.ByLabel,
+		})
+		os.Exit(1)
+	}
+
+	written := ""
+	if !dryRun {
+		if err := config.SaveSection(beatsDir, ripeness.ConfigSection, result.Profile); err != nil {
+			fatalJSON("error", err.Error())
+		}
+		written = filepath.Join(beatsDir, config.ProjectFileName)
+	}
+
+	outputJSON(map[string]interface{}{
+		"calibration": result,
+		"dry_run":     dryRun,
+		"written_to":  written,
+	})
+}
+
+func robotRipe() {
+	enriched, cache, err := getEnrichedBeats()
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+
+	limit := 10
+	threshold := 0.0
+	for i, arg := range os.Args {
+		if arg == "--limit" && i+1 < len(os.Args) {
+			if n, err := strconv.Atoi(os.Args[i+1]); err == nil {
+				limit = n
+			}
+		}
+		if arg == "--threshold" && i+1 < len(os.Args) {
+			if f, err := strconv.ParseFloat(os.Args[i+1], 64); err == nil {
+				threshold = f
+			}
+		}
+	}
+
+	d := &dataset{projects: []projectData{{enriched: enriched, cache: cache}}}
+	outputJSON(d.ripe(limit, threshold))
+}
+
+// ripe lists the ripest beats scoring at least threshold, ripest first
+func (d *dataset) ripe(limit int, threshold float64) map[string]interface{} {
+	// Each beat is tiered by its own project's thresholds
+	type scored struct {
+		model.EnrichedBeat
+		tier string
+	}
+	var filtered []scored
+	for _, pd := range d.projects {
+		for _, eb := range pd.enriched {
+			if eb.RipenessScore >= threshold {
+				filtered = append(filtered, scored{eb, pd.cache.RipenessTiers.Tier(eb.RipenessScore)})
+			}
+		}
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].RipenessScore > filtered[j].RipenessScore
+	})
+
+	limit = max(limit, 0)
+	if len(filtered) > limit {
+		filtered = filtered[:limit]
+	}
+
+	var results []map[string]interface{}
+	for _, eb := range filtered {
+		results = append(results, map[string]interface{}{
+			"id":       eb.ID,
+			"ripeness": eb.RipenessScore,
+			"tier":     eb.tier,
+			"state":    eb.RipenessState,
+			"preview":  eb.ContentPreview(80),
+		})
+	}
+
+	return map[string]interface{}{"beats": results, "count": len(results)}
+}
+
+func robotEntities() {
+	_, cache, err := getEnrichedBeats()
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	d := &dataset{projects: []projectData{{cache: cache}}}
+	outputJSON(d.entities())
+}
+
+// entities lists the people, tools and concepts the beats mention
+func (d *dataset) entities() map[string]interface{} {
+	// An entity mentioned in several projects is counted across them
+	var merged []model.Entity
+	index := make(map[string]int)
+	for _, pd := range d.projects {
+		for _, e := range pd.cache.Entities {
+			if i, ok := index[e.ID]; ok {
+				merged[i].BeatIDs = append(merged[i].BeatIDs, e.BeatIDs...)
+				continue
+			}
+			index[e.ID] = len(merged)
+			e.BeatIDs = append([]string(nil), e.BeatIDs...)
+			merged = append(merged, e)
+		}
+	}
+
+	var people, tools, concepts []map[string]interface{}
+	for _, e := range merged {
+		item := map[string]interface{}{
+			"id":         e.ID,
+			"name":       e.Name,
+			"beat_count": len(e.BeatIDs),
+		}
+		switch e.Type {
+		case model.EntityPerson:
+			people = append(people, item)
+		case model.EntityTool:
+			tools = append(tools, item)
+		case model.EntityConcept:
+			concepts = append(concepts, item)
+		}
+	}
+
+	return map[string]interface{}{
+		"people":   people,
+		"tools":    tools,
+		"concepts": concepts,
+	}
+}
+
+func robotEntityDictionary() {
+	beatsDir, err := getBeatsDir()
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+
+	dict, sources, err := entity.LoadDictionary(beatsDir)
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	if sources == nil {
+		sources = []string{}
+	}
+
+	entries := make(map[string][]string)
+	for _, t := range model.AllEntityTypes() {
+		entries[strings.ToLower(t.String())] = dict.Entries[t]
+	}
+	var ignore []string
+	for name := range dict.Ignore {
+		ignore = append(ignore, name)
+	}
+	sort.Strings(ignore)
+
+	llmConfig, _, err := entity.LoadLLMConfig(beatsDir)
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	extractor := map[string]interface{}{"name": dict.Name()}
+	if llmConfig.Enabled {
+		llm := entity.NewLLMExtractor(dict, llmConfig, nil, beatsDir)
+		extractor["name"] = llm.Name()
+		extractor["llm"] = map[string]interface{}{
+			"api":       llmConfig.API,
+			"url":       llmConfig.URL,
+			"model":     llmConfig.Model,
+			"available": llm.Available(),
+		}
+	}
+
+	outputJSON(map[string]interface{}{
+		"entries":     entries,
+		"aliases":     dict.Aliases,
+		"case":        dict.Case,
+		"ignore":      ignore,
+		"fingerprint": dict.Fingerprint(),
+		"extractor":   extractor,
+		"sources":     sources,
+		"search_path": append(config.Paths(beatsDir), entity.DictionaryPaths(beatsDir)...),
+	})
+}
+
+func robotCurateEntity(op, name, arg string) {
+	beatsDir, err := getBeatsDir()
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+
+	curation, err := entity.LoadCuration(beatsDir)
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+
+	resp := map[string]interface{}{"op": op, "name": name}
+	switch op {
+	case "merge":
+		// Keep the target's current type so merging does not reclassify it
+		intoType := model.EntityPerson
+		if cache, err := loader.EnsureCache(beatsDir, nil); err == nil {
+			for _, e := range cache.Entities {
+				if strings.EqualFold(e.Name, arg) {
+					intoType = e.Type
+					break
+				}
+			}
+		}
+		if dict, _, err := entity.LoadDictionary(beatsDir); err == nil {
+			if t, ok := dict.TypeOf(arg); ok {
+				intoType = t
+			}
+		}
+		if err := curation.MergeEntity(name, arg, intoType); err != nil {
+			fatalJSON("error", err.Error())
+		}
+		resp["into"] = arg
+	case "retype":
+		t, err := model.ParseEntityType(arg)
+		if err != nil {
+			fatalJSON("error", err.Error())
+		}
+		curation.Retype(name, t)
+		resp["type"] = strings.ToLower(t.String())
+	case "promote":
+		curation.Promote(name)
+	case "hide":
+		curation.Hide(name)
+	case "unhide":
+		curation.Unhide(name)
+	case "split":
+		restored := curation.Split(name)
+		if len(restored) == 0 {
+			fatalJSON("error", "nothing is merged into or from "+name)
+		}
+		resp["restored"] = restored
 	}
-	return loader.LoadEnrichedBeats(projects[0].Path, nil)
-}
 
-func robotTaxonomyStats() {
-	enriched, cache, err := getEnrichedBeats()
-	if err != nil {
+	if err := entity.SaveCuration(beatsDir, curation); err != nil {
 		fatalJSON("error", err.Error())
 	}
 
-	channels := make(map[string]int)
-	sources := make(map[string]int)
-
-	for _, eb := range enriched {
-		tax := cache.Taxonomies[eb.ID]
-		channels[tax.Channel.String()]++
-		sources[tax.Source.String()]++
+	cache, err := loader.EnsureCache(beatsDir, nil)
+	if err != nil {
+		fatalJSON("error", err.Error())
 	}
 
-	resp := map[string]interface{}{
-		"channels": channels,
-		"sources":  sources,
-		"total":    len(enriched),
-	}
+	resp["curation"] = curation
+	resp["entities"] = len(cache.Entities)
+	resp["written_to"] = filepath.Join(beatsDir, entity.CurationFileName)
 	outputJSON(resp)
 }
 
-func robotRipeness(beatID string) {
-	enriched, cache, err := getEnrichedBeats()
+func robotEntityGraph() {
+	_, cache, err := getEnrichedBeats()
 	if err != nil {
 		fatalJSON("error", err.Error())
 	}
 
-	var beats []model.Beat
-	for _, eb := range enriched {
-		beats = append(beats, eb.Beat)
+	format := "json"
+	byPMI := false
+	minShared := 1
+	focus := ""
+	for i, arg := range os.Args {
+		if arg == "--format" && i+1 < len(os.Args) {
+			format = os.Args[i+1]
+		}
+		if arg == "--pmi" {
+			byPMI = true
+		}
+		if arg == "--min-shared" && i+1 < len(os.Args) {
+			if n, err := strconv.Atoi(os.Args[i+1]); err == nil {
+				minShared = n
+			}
+		}
+		if arg == "--entity" && i+1 < len(os.Args) {
+			focus = os.Args[i+1]
+		}
 	}
 
-	var target *model.Beat
-	for _, b := range beats {
-		if b.ID == beatID {
-			target = &b
-			break
+	graph := entity.BuildGraph(cache.Entities, minShared)
+	if focus != "" {
+		if len(graph.Resolve(focus)) == 0 {
+			fatalJSON("error", "entity not found: "+focus)
+		}
+		graph = graph.Neighborhood(focus)
+	}
+
+	switch format {
+	case "dot":
+		fmt.Print(graph.DOT(byPMI))
+	case "json":
+		nodes := []map[string]interface{}{}
+		for _, n := range graph.Nodes {
+			nodes = append(nodes, map[string]interface{}{
+				"id":    n.ID,
+				"name":  n.Name,
+				"type":  strings.ToLower(n.Type.String()),
+				"beats": n.Beats,
+			})
+		}
+		edges := []map[string]interface{}{}
+		for _, e := range graph.Edges {
+			edges = append(edges, map[string]interface{}{
+				"source_id": e.SourceID,
+				"target_id": e.TargetID,
+				"source":    e.Source,
+				"target":    e.Target,
+				"shared":    e.Shared,
+				"pmi":       e.PMI,
+				"npmi":      e.NPMI,
+				"weight":    e.Weight(byPMI),
+			})
 		}
+		resp := map[string]interface{}{
+			"beats":  graph.Beats,
+			"nodes":  nodes,
+			"edges":  edges,
+			"weight": "shared",
+		}
+		if byPMI {
+			resp["weight"] = "npmi"
+		}
+		if focus != "" {
+			resp["entity"] = focus
+		}
+		outputJSON(resp)
+	default:
+		fatalJSON("error", "unknown format: "+format)
 	}
-	if target == nil {
-		fatalJSON("error", "beat not found: "+beatID)
+}
+
+func robotMentions(beatID string) {
+	enriched, _, err := getEnrichedBeats()
+	if err != nil {
+		fatalJSON("error", err.Error())
 	}
 
-	viewStat := cache.ViewStats[beatID]
-	breakdown := ripeness.CalculateWithBreakdown(*target, beats, viewStat)
+	for _, eb := range enriched {
+		if eb.ID != beatID {
+			continue
+		}
+		mentions := []map[string]interface{}{}
+		for _, m := range eb.Mentions {
+			mentions = append(mentions, mentionJSON(m))
+		}
+		outputJSON(map[string]interface{}{
+			"beat_id":  beatID,
+			"mentions": mentions,
+			"count":    len(mentions),
+		})
+		return
+	}
+	fatalJSON("error", "beat not found: "+beatID)
+}
 
-	resp := map[string]interface{}{
-		"beat_id": beatID,
-		"score":   breakdown.Total,
-		"tier":    model.RipenessTier(breakdown.Total),
-		"factors": map[string]float64{
-			"age":          breakdown.Age,
-			"revisit":      breakdown.Revisit,
-			"connection":   breakdown.Connection,
-			"action":       breakdown.Action,
-			"completeness": breakdown.Completeness,
-		},
+func mentionJSON(m model.Mention) map[string]interface{} {
+	return map[string]interface{}{
+		"id":      m.EntityID(),
+		"name":    m.Name,
+		"type":    strings.ToLower(m.Type.String()),
+		"start":   m.Start,
+		"end":     m.End,
+		"surface": m.Surface,
 	}
-	outputJSON(resp)
 }
 
-func robotRipe() {
-	enriched, _, err := getEnrichedBeats()
+// robotEntityBeats lists the beats mentioning an entity, given by ID (e.g.
+// "tool:go") or by name, which matches every type sharing it
+func robotEntityBeats(key string) {
+	enriched, cache, err := getEnrichedBeats()
 	if err != nil {
 		fatalJSON("error", err.Error())
 	}
 
-	limit := 10
-	threshold := 0.0
-	for i, arg := range os.Args {
-		if arg == "--limit" && i+1 < len(os.Args) {
-			if n, err := strconv.Atoi(os.Args[i+1]); err == nil {
-				limit = n
-			}
+	matches := func(m model.Mention) bool { return strings.EqualFold(m.Name, key) }
+	var ids []string
+	if _, _, err := model.ParseEntityID(key); err == nil {
+		key = strings.ToLower(key)
+		matches = func(m model.Mention) bool { return m.EntityID() == key }
+		if _, ok := cache.EntityIndex[key]; ok {
+			ids = append(ids, key)
 		}
-		if arg == "--threshold" && i+1 < len(os.Args) {
-			if f, err := strconv.ParseFloat(os.Args[i+1], 64); err == nil {
-				threshold = f
+	} else {
+		for _, e := range cache.Entities {
+			if strings.EqualFold(e.Name, key) {
+				ids = append(ids, e.ID)
 			}
 		}
 	}
+	sort.Strings(ids)
 
-	var filtered []model.EnrichedBeat
-	for _, eb := range enriched {
-		if eb.RipenessScore >= threshold {
-			filtered = append(filtered, eb)
+	idSet := make(map[string]bool)
+	for _, id := range ids {
+		for _, beatID := range cache.EntityIndex[id] {
+			idSet[beatID] = true
 		}
 	}
-
-	sort.Slice(filtered, func(i, j int) bool {
-		return filtered[i].RipenessScore > filtered[j].RipenessScore
-	})
-
-	if len(filtered) > limit {
-		filtered = filtered[:limit]
+	if len(idSet) == 0 {
+		outputJSON(map[string]interface{}{"beats": []interface{}{}, "entity": key, "ids": []string{}, "count": 0})
+		return
 	}
 
 	var results []map[string]interface{}
-	for _, eb := range filtered {
-		results = append(results, map[string]interface{}{
-			"id":       eb.ID,
-			"ripeness": eb.RipenessScore,
-			"tier":     model.RipenessTier(eb.RipenessScore),
-			"preview":  eb.ContentPreview(80),
-		})
+	for _, eb := range enriched {
+		if idSet[eb.ID] {
+			var spans []map[string]interface{}
+			for _, m := range eb.Mentions {
+				if matches(m) {
+					spans = append(spans, mentionJSON(m))
+				}
+			}
+			results = append(results, map[string]interface{}{
+				"id":       eb.ID,
+				"preview":  eb.ContentPreview(80),
+				"mentions": spans,
+			})
+		}
 	}
 
-	outputJSON(map[string]interface{}{"beats": results, "count": len(results)})
+	outputJSON(map[string]interface{}{"beats": results, "entity": key, "ids": ids, "count": len(results)})
 }
 
-func robotEntities() {
-	_, cache, err := getEnrichedBeats()
+func robotEntityProfile(name string) {
+	enriched, cache, err := getEnrichedBeats()
 	if err != nil {
 		fatalJSON("error", err.Error())
 	}
 
-	var people, tools, concepts []map[string]interface{}
-	for _, e := range cache.Entities {
-		item := map[string]interface{}{
-			"name":       e.Name,
-			"beat_count": len(e.BeatIDs),
-		}
-		switch e.Type {
-		case model.EntityPerson:
-			people = append(people, item)
-		case model.EntityTool:
-			tools = append(tools, item)
-		case model.EntityConcept:
-			concepts = append(concepts, item)
-		}
+	graph := entity.BuildGraph(cache.Entities, 1)
+	profile, ok := entity.BuildProfile(name, enriched, cache.Entities, graph, 0)
+	if !ok {
+		fatalJSON("error", "entity not found: "+name)
+	}
+	profile.NameChains(cache.Chains)
+	profile.NameClusters(cache.Clusters)
+
+	mentions := []map[string]interface{}{}
+	for _, mc := range profile.Mentions {
+		mentions = append(mentions, map[string]interface{}{
+			"beat_id":    mc.BeatID,
+			"created_at": mc.CreatedAt.Format(time.RFC3339),
+			"surface":    mc.Mention.Surface,
+			"snippet":    mc.Snippet,
+			"start":      mc.Start,
+			"end":        mc.End,
+		})
+	}
+	related := []map[string]interface{}{}
+	for _, e := range profile.Related {
+		related = append(related, map[string]interface{}{
+			"id":     e.TargetID,
+			"name":   e.Target,
+			"shared": e.Shared,
+			"npmi":   e.NPMI,
+		})
 	}
 
 	outputJSON(map[string]interface{}{
-		"people":   people,
-		"tools":    tools,
-		"concepts": concepts,
+		"name":         profile.Name,
+		"type":         strings.ToLower(profile.Type.String()),
+		"beat_ids":     profile.BeatIDs,
+		"first_seen":   profile.FirstSeen.Format(time.RFC3339),
+		"last_seen":    profile.LastSeen.Format(time.RFC3339),
+		"surfaces":     profile.Surfaces,
+		"mentions":     mentions,
+		"channels":     profile.Channels,
+		"sources":      profile.Sources,
+		"related":      related,
+		"linked_beads": profile.LinkedBeads,
+		"chains":       profile.Chains,
+		"clusters":     profile.Clusters,
 	})
 }
 
-func robotEntityBeats(entityName string) {
+func robotEntityTrends() {
 	enriched, cache, err := getEnrichedBeats()
 	if err != nil {
 		fatalJSON("error", err.Error())
 	}
 
-	beatIDs := cache.EntityIndex[entityName]
-	if len(beatIDs) == 0 {
-		outputJSON(map[string]interface{}{"beats": []interface{}{}, "entity": entityName})
-		return
+	zoom := timeline.ZoomMonth
+	periods := 1
+	limit := 0
+	focus := ""
+	for i, arg := range os.Args {
+		if i+1 >= len(os.Args) {
+			break
+		}
+		switch arg {
+		case "--zoom":
+			z, ok := timeline.ParseZoomLevel(os.Args[i+1])
+			if !ok {
+				fatalJSON("error", "unknown zoom level: "+os.Args[i+1])
+			}
+			zoom = z
+		case "--window":
+			if n, err := strconv.Atoi(os.Args[i+1]); err == nil {
+				periods = n
+			}
+		case "--limit":
+			if n, err := strconv.Atoi(os.Args[i+1]); err == nil {
+				limit = n
+			}
+		case "--entity":
+			focus = os.Args[i+1]
+		}
 	}
 
-	idSet := make(map[string]bool)
-	for _, id := range beatIDs {
-		idSet[id] = true
-	}
+	data := timeline.BuildTimeline(enriched, zoom)
+	trends, window := entity.BuildTrends(cache.Entities, data, periods)
 
-	var results []map[string]interface{}
-	for _, eb := range enriched {
-		if idSet[eb.ID] {
-			results = append(results, map[string]interface{}{
-				"id":      eb.ID,
-				"preview": eb.ContentPreview(80),
+	results := []map[string]interface{}{}
+	for _, t := range trends {
+		if focus != "" && !strings.EqualFold(t.Name, focus) {
+			continue
+		}
+		series := []map[string]interface{}{}
+		for _, p := range t.Series {
+			series = append(series, map[string]interface{}{
+				"date":  p.Date.Format("2006-01-02"),
+				"count": p.Count,
 			})
 		}
+		results = append(results, map[string]interface{}{
+			"name":     t.Name,
+			"type":     strings.ToLower(t.Type.String()),
+			"trend":    string(t.Trend),
+			"recent":   t.Recent,
+			"previous": t.Previous,
+			"change":   t.Change,
+			"series":   series,
+		})
+		if limit > 0 && len(results) >= limit {
+			break
+		}
+	}
+	if focus != "" && len(results) == 0 {
+		fatalJSON("error", "no recent or previous mentions of: "+focus)
 	}
 
-	outputJSON(map[string]interface{}{"beats": results, "entity": entityName, "count": len(results)})
+	resp := map[string]interface{}{
+		"zoom_level": zoom.String(),
+		"window":     window.Periods,
+		"trends":     results,
+		"count":      len(results),
+	}
+	if !window.RecentStart.IsZero() {
+		resp["recent_start"] = window.RecentStart.Format("2006-01-02")
+		resp["previous_start"] = window.PreviousStart.Format("2006-01-02")
+	}
+	outputJSON(resp)
 }
 
 func robotTimeline() {
@@ -499,20 +1816,19 @@ func robotTimeline() {
 	zoom := timeline.ZoomMonth
 	for i, arg := range os.Args {
 		if arg == "--zoom" && i+1 < len(os.Args) {
-			switch os.Args[i+1] {
-			case "day":
-				zoom = timeline.ZoomDay
-			case "week":
-				zoom = timeline.ZoomWeek
-			case "month":
-				zoom = timeline.ZoomMonth
-			case "quarter":
-				zoom = timeline.ZoomQuarter
+			if z, ok := timeline.ParseZoomLevel(os.Args[i+1]); ok {
+				zoom = z
 			}
 		}
 	}
 
-	data := timeline.BuildTimeline(enriched, zoom)
+	d := &dataset{projects: []projectData{{enriched: enriched}}}
+	outputJSON(d.timeline(zoom))
+}
+
+// timeline counts beats per period at a zoom level
+func (d *dataset) timeline(zoom timeline.ZoomLevel) map[string]interface{} {
+	data := timeline.BuildTimeline(d.enriched(), zoom)
 
 	var buckets []map[string]interface{}
 	for _, b := range data.Buckets {
@@ -527,12 +1843,12 @@ func robotTimeline() {
 		})
 	}
 
-	outputJSON(map[string]interface{}{
+	return map[string]interface{}{
 		"buckets":    buckets,
 		"zoom_level": zoom.String(),
 		"start":      data.Start.Format("2006-01-02"),
 		"end":        data.End.Format("2006-01-02"),
-	})
+	}
 }
 
 func robotGaps() {
@@ -566,7 +1882,11 @@ func robotGaps() {
 }
 
 func robotCluster() {
-	enriched, cache, err := getEnrichedBeats()
+	beatsDir, err := getBeatsDir()
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	enriched, cache, err := loader.LoadEnrichedBeats(beatsDir, nil)
 	if err != nil {
 		fatalJSON("error", err.Error())
 	}
@@ -599,6 +1919,14 @@ func robotCluster() {
 
 	cache.Clusters = clusters
 	cache.EmbeddingsAvailable = true
+	cache.EmbeddingNeighbors = engine.Neighbors(cluster.DefaultNeighborCount)
+	// Rescore so the new neighbours feed the connection factor
+	if err := loader.RescoreRipeness(beatsDir, cache); err != nil {
+		fatalJSON("error", err.Error())
+	}
+	if err := loader.SaveCache(beatsDir, cache); err != nil {
+		fatalJSON("error", err.Error())
+	}
 
 	var result []map[string]interface{}
 	for _, c := range clusters {
@@ -619,23 +1947,57 @@ func robotClusters() {
 	if err != nil {
 		fatalJSON("error", err.Error())
 	}
+	d := &dataset{projects: []projectData{{cache: cache}}}
+	outputJSON(d.clusters())
+}
 
+// clusters lists the theme clusters
+func (d *dataset) clusters() map[string]interface{} {
 	var result []map[string]interface{}
-	for _, c := range cache.Clusters {
-		result = append(result, map[string]interface{}{
-			"id":         c.ID,
-			"name":       c.Name,
-			"beat_count": len(c.BeatIDs),
-			"keywords":   c.Keywords,
-			"ripeness":   c.RipenessScore,
-		})
+	embeddings := false
+	for _, pd := range d.projects {
+		for _, c := range pd.cache.Clusters {
+			result = append(result, map[string]interface{}{
+				"id":         c.ID,
+				"name":       c.Name,
+				"beat_count": len(c.BeatIDs),
+				"keywords":   c.Keywords,
+				"ripeness":   c.RipenessScore,
+			})
+		}
+		embeddings = embeddings || pd.cache.EmbeddingsAvailable
 	}
 
-	outputJSON(map[string]interface{}{
+	return map[string]interface{}{
 		"clusters":             result,
 		"count":                len(result),
-		"embeddings_available": cache.EmbeddingsAvailable,
-	})
+		"embeddings_available": embeddings,
+	}
+}
+
+// cluster describes one theme cluster with its beats
+func (d *dataset) cluster(clusterID string) (map[string]interface{}, error) {
+	for _, pd := range d.projects {
+		for _, c := range pd.cache.Clusters {
+			if c.ID != clusterID {
+				continue
+			}
+			beats := make([]model.BeatListItem, 0, len(c.BeatIDs))
+			for _, id := range c.BeatIDs {
+				if b := loader.FindBeatByID(d.beats, id); b != nil {
+					beats = append(beats, b.ToListItem(d.beatToProject[b.ID], 80))
+				}
+			}
+			return map[string]interface{}{
+				"id":       c.ID,
+				"name":     c.Name,
+				"keywords": c.Keywords,
+				"ripeness": c.RipenessScore,
+				"beats":    beats,
+			}, nil
+		}
+	}
+	return nil, errNotFound("cluster not found: " + clusterID)
 }
 
 func robotSimilar(beatID string) {
@@ -644,17 +2006,6 @@ func robotSimilar(beatID string) {
 		fatalJSON("error", err.Error())
 	}
 
-	var target *model.EnrichedBeat
-	for _, eb := range enriched {
-		if eb.ID == beatID {
-			target = &eb
-			break
-		}
-	}
-	if target == nil {
-		fatalJSON("error", "beat not found: "+beatID)
-	}
-
 	limit := 5
 	for i, arg := range os.Args {
 		if arg == "--limit" && i+1 < len(os.Args) {
@@ -664,13 +2015,34 @@ func robotSimilar(beatID string) {
 		}
 	}
 
+	d := &dataset{projects: []projectData{{enriched: enriched}}}
+	resp, err := d.similar(beatID, limit)
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	outputJSON(resp)
+}
+
+// similar finds the beats closest in meaning to one through Ollama embeddings
+func (d *dataset) similar(beatID string, limit int) (map[string]interface{}, error) {
+	enriched := d.enriched()
+	var target *model.EnrichedBeat
+	for i := range enriched {
+		if enriched[i].ID == beatID {
+			target = &enriched[i]
+			break
+		}
+	}
+	if target == nil {
+		return nil, errNotFound("beat not found: " + beatID)
+	}
+
 	engine := cluster.NewEngine()
 	if !engine.IsAvailable() {
-		outputJSON(map[string]interface{}{
+		return map[string]interface{}{
 			"error":   "ollama not available",
 			"message": "Install Ollama for similarity search",
-		})
-		return
+		}, nil
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
@@ -678,7 +2050,7 @@ func robotSimilar(beatID string) {
 
 	similar, err := engine.FindSimilar(ctx, *target, enriched, limit)
 	if err != nil {
-		fatalJSON("error", err.Error())
+		return nil, err
 	}
 
 	var result []map[string]interface{}
@@ -689,7 +2061,7 @@ func robotSimilar(beatID string) {
 		})
 	}
 
-	outputJSON(map[string]interface{}{"similar": result, "source_beat": beatID})
+	return map[string]interface{}{"similar": result, "source_beat": beatID}, nil
 }
 
 func robotChains() {
@@ -697,18 +2069,25 @@ func robotChains() {
 	if err != nil {
 		fatalJSON("error", err.Error())
 	}
+	d := &dataset{projects: []projectData{{cache: cache}}}
+	outputJSON(d.chains())
+}
 
+// chains lists the chains of beats
+func (d *dataset) chains() map[string]interface{} {
 	var result []map[string]interface{}
-	for _, c := range cache.Chains {
-		result = append(result, map[string]interface{}{
-			"id":         c.ID,
-			"name":       c.Name,
-			"beat_count": len(c.BeatIDs),
-			"ripeness":   c.RipenessScore,
-		})
+	for _, pd := range d.projects {
+		for _, c := range pd.cache.Chains {
+			result = append(result, map[string]interface{}{
+				"id":         c.ID,
+				"name":       c.Name,
+				"beat_count": len(c.BeatIDs),
+				"ripeness":   c.RipenessScore,
+			})
+		}
 	}
 
-	outputJSON(map[string]interface{}{"chains": result, "count": len(result)})
+	return map[string]interface{}{"chains": result, "count": len(result)}
 }
 
 func robotCreateChain() {
@@ -721,20 +2100,18 @@ func robotCreateChain() {
 		fatalJSON("error", "invalid JSON input: "+err.Error())
 	}
 
-	if input.Name == "" {
-		fatalJSON("error", "chain name required")
+	beatsDir, err := getBeatsDir()
+	if err != nil {
+		fatalJSON("error", err.Error())
 	}
-
-	chain := model.Chain{
-		ID:        fmt.Sprintf("chain-%d", time.Now().UnixNano()),
-		Name:      input.Name,
-		BeatIDs:   input.BeatIDs,
-		CreatedAt: time.Now(),
+	c, err := createChain(beatsDir, input.Name, input.BeatIDs)
+	if err != nil {
+		fatalJSON("error", err.Error())
 	}
 
 	outputJSON(map[string]interface{}{
-		"chain":   chain,
-		"message": "Chain created (note: not persisted without cache save)",
+		"chain":   c,
+		"message": "Chain created",
 	})
 }
 
@@ -748,14 +2125,70 @@ func robotChainAdd() {
 		fatalJSON("error", "invalid JSON input: "+err.Error())
 	}
 
+	beatsDir, err := getBeatsDir()
+	if err != nil {
+		fatalJSON("error", err.Error())
+	}
+	if err := addToChain(beatsDir, input.ChainID, input.BeatID); err != nil {
+		fatalJSON("error", err.Error())
+	}
+
 	outputJSON(map[string]interface{}{
 		"success":  true,
 		"chain_id": input.ChainID,
 		"beat_id":  input.BeatID,
-		"message":  "Beat added to chain (note: not persisted without cache save)",
+		"message":  "Beat added to chain",
 	})
 }
 
+// createChain saves a new chain of beats in the cache of the project at
+// beatsDir
+func createChain(beatsDir, name string, beatIDs []string) (*model.Chain, error) {
+	var created *model.Chain
+	err := updateChains(beatsDir, beatIDs, func(store *chain.Store) error {
+		c, err := store.Create(name, beatIDs)
+		created = c
+		return err
+	})
+	return created, err
+}
+
+// addToChain adds a beat to a saved chain
+func addToChain(beatsDir, chainID, beatID string) error {
+	return updateChains(beatsDir, []string{beatID}, func(store *chain.Store) error {
+		if store.Get(chainID) == nil {
+			return errNotFound("chain not found: " + chainID)
+		}
+		return store.AddBeat(chainID, beatID)
+	})
+}
+
+// updateChains checks that beatIDs exist, applies fn to the project's chains
+// and saves them
+func updateChains(beatsDir string, beatIDs []string, fn func(*chain.Store) error) error {
+	beats, err := loader.LoadBeats(beatsDir)
+	if err != nil {
+		return err
+	}
+	for _, id := range beatIDs {
+		if loader.FindBeatByID(beats, id) == nil {
+			return errNotFound("beat not found: " + id)
+		}
+	}
+
+	cache, err := loader.EnsureCache(beatsDir, nil)
+	if err != nil {
+		return err
+	}
+	store := chain.NewStore()
+	store.LoadFromCache(cache.Chains)
+	if err := fn(store); err != nil {
+		return err
+	}
+	cache.Chains = store.List()
+	return loader.SaveCache(beatsDir, cache)
+}
+
 type StaleReason struct {
 	Code       string `json:"code"`
 	Message    string `json:"message"`
@@ -821,8 +2254,13 @@ func robotStale() {
 	if err != nil {
 		fatalJSON("error", err.Error())
 	}
+	d := &dataset{projects: []projectData{{enriched: enriched}}}
+	outputJSON(d.stale())
+}
 
-	stale := views.FindStaleBeats(enriched)
+// stale lists beats gone stale, with why and what to do about them
+func (d *dataset) stale() map[string]interface{} {
+	stale := views.FindStaleBeats(d.enriched())
 
 	var result []map[string]interface{}
 	for _, eb := range stale {
@@ -844,7 +2282,7 @@ func robotStale() {
 		})
 	}
 
-	outputJSON(map[string]interface{}{"stale_beats": result, "count": len(result)})
+	return map[string]interface{}{"stale_beats": result, "count": len(result)}
 }
 
 func rebuildCache() {
@@ -903,6 +2341,16 @@ func rebuildCache() {
 }
 
 func runCapture() {
-	fmt.Println("Quick capture mode - implement with minimal TUI")
-	fmt.Println("For now, use: bt add \"your insight\"")
+	if len(os.Args) < 3 {
+		fatal("--capture requires the beat's text, e.g. btv --capture \"your insight\"")
+	}
+	beatsDir, err := getBeatsDir()
+	if err != nil {
+		fatal(err.Error())
+	}
+	beat, err := capture.Add(beatsDir, strings.Join(os.Args[2:], " "))
+	if err != nil {
+		fatal(err.Error())
+	}
+	fmt.Printf("Captured %s\n", beat.ID)
 }
@@ -0,0 +1,554 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/bierlingm/beats_viewer/pkg/capture"
+)
+
+// mcpProtocolVersions are the Model Context Protocol revisions btv mcp
+// speaks, newest first
+var mcpProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes
+const (
+	rpcParseError       = -32700
+	rpcInvalidRequest   = -32600
+	rpcMethodNotFound   = -32601
+	rpcInvalidParams    = -32602
+	mcpResourceNotFound = -32002
+)
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"` // absent for notifications
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// mcpTool is a tool offered to the client and what runs it
+type mcpTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+
+	call func(s *server, args json.RawMessage) (interface{}, error)
+}
+
+func runMCP() {
+	s := &server{rootPath: rootArg()}
+	if err := s.load(); err != nil {
+		fatal(err.Error())
+	}
+	go s.watch()
+
+	if err := serveMCP(s, os.Stdin, os.Stdout); err != nil {
+		fatal(err.Error())
+	}
+}
+
+// serveMCP answers newline-delimited JSON-RPC messages from in until it ends
+func serveMCP(s *server, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	enc := json.NewEncoder(out)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var req rpcRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			resp := rpcResponse{JSONRPC: "2.0", Error: &rpcError{rpcParseError, "parse error: " + err.Error()}}
+			if err := enc.Encode(resp); err != nil {
+				return err
+			}
+			continue
+		}
+
+		result, rpcErr := s.handleMCP(req)
+		if len(req.ID) == 0 {
+			continue // notifications get no reply
+		}
+		resp := rpcResponse{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rpcErr}
+		if rpcErr != nil {
+			resp.Result = nil
+		}
+		if err := enc.Encode(resp); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func (s *server) handleMCP(req rpcRequest) (interface{}, *rpcError) {
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return nil, &rpcError{rpcInvalidRequest, "invalid request"}
+	}
+
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		json.Unmarshal(req.Params, &params)
+		// Answer with the client's revision when we speak it, else our newest
+		protocol := mcpProtocolVersions[0]
+		if slices.Contains(mcpProtocolVersions, params.ProtocolVersion) {
+			protocol = params.ProtocolVersion
+		}
+		return map[string]interface{}{
+			"protocolVersion": protocol,
+			"capabilities": map[string]interface{}{
+				"tools":     map[string]interface{}{},
+				"resources": map[string]interface{}{},
+			},
+			"serverInfo":   map[string]string{"name": "btv", "version": version},
+			"instructions": "Beats are captured insights. Search or list them, read ripe and stale ones, and act on them by chaining, reclassifying or capturing new beats.",
+		}, nil
+	case "ping":
+		return map[string]interface{}{}, nil
+	case "tools/list":
+		return map[string]interface{}{"tools": mcpTools}, nil
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{rpcInvalidParams, "invalid params: " + err.Error()}
+		}
+		for _, t := range mcpTools {
+			if t.Name == params.Name {
+				v, err := t.call(s, params.Arguments)
+				// Arguments the tool can't use are the caller's mistake
+				var bad badRequest
+				if errors.As(err, &bad) {
+					return nil, &rpcError{rpcInvalidParams, err.Error()}
+				}
+				return toolResult(v, err), nil
+			}
+		}
+		return nil, &rpcError{rpcInvalidParams, "unknown tool: " + params.Name}
+	case "resources/list":
+		return map[string]interface{}{"resources": mcpResources}, nil
+	case "resources/templates/list":
+		return map[string]interface{}{"resourceTemplates": mcpResourceTemplates}, nil
+	case "resources/read":
+		var params struct {
+			URI string `json:"uri"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{rpcInvalidParams, "invalid params: " + err.Error()}
+		}
+		v, err := readResource(s.snapshot(), params.URI)
+		if err != nil {
+			return nil, &rpcError{mcpResourceNotFound, err.Error()}
+		}
+		text, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return nil, &rpcError{rpcInvalidParams, err.Error()}
+		}
+		return map[string]interface{}{
+			"contents": []map[string]string{{"uri": params.URI, "mimeType": "application/json", "text": string(text)}},
+		}, nil
+	}
+
+	if strings.HasPrefix(req.Method, "notifications/") {
+		return nil, nil
+	}
+	return nil, &rpcError{rpcMethodNotFound, "method not found: " + req.Method}
+}
+
+// projectDir is the beats directory of the named project, or of the first
+// project when no name is given
+func (s *server) projectDir(name string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if name == "" {
+		return s.beatsDir, nil
+	}
+	if dir, ok := s.dirs[name]; ok {
+		return dir, nil
+	}
+	return "", badRequest("unknown project: " + name)
+}
+
+// beatDir is the beats directory of the project holding a beat, where
+// changes to it are made
+func (s *server) beatDir(beatID string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if dir, ok := s.beatDirs[beatID]; ok {
+		return dir, nil
+	}
+	return "", errNotFound("beat not found: " + beatID)
+}
+
+// chainDir is where a new chain of beats goes: the named project, or else
+// the project holding its beats, which must all be in one
+func (s *server) chainDir(project string, beatIDs []string) (string, error) {
+	if project != "" || len(beatIDs) == 0 {
+		return s.projectDir(project)
+	}
+	dir, err := s.beatDir(beatIDs[0])
+	if err != nil {
+		return "", err
+	}
+	for _, id := range beatIDs[1:] {
+		other, err := s.beatDir(id)
+		if err != nil {
+			return "", err
+		}
+		if other != dir {
+			return "", badRequest("a chain's beats must all be in one project")
+		}
+	}
+	return dir, nil
+}
+
+// reload picks up a change a tool made; the change itself stands if this fails
+func (s *server) reload() {
+	if err := s.load(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error reloading: %v\n", err)
+	}
+}
+
+// toolResult wraps a tool's JSON output, or its error, as MCP text content
+func toolResult(v interface{}, err error) map[string]interface{} {
+	if err != nil {
+		return map[string]interface{}{
+			"content": []map[string]string{{"type": "text", "text": err.Error()}},
+			"isError": true,
+		}
+	}
+	text, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return toolResult(nil, err)
+	}
+	return map[string]interface{}{
+		"content": []map[string]string{{"type": "text", "text": string(text)}},
+		"isError": false,
+	}
+}
+
+// decodeArgs reads a tool's arguments, which may be left out
+func decodeArgs(args json.RawMessage, v interface{}) error {
+	if len(args) == 0 || string(args) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(args, v); err != nil {
+		return badRequest("invalid arguments: " + err.Error())
+	}
+	return nil
+}
+
+// required reports an argument left out or empty
+func required(name, value string) error {
+	if value == "" {
+		return badRequest(name + " is required")
+	}
+	return nil
+}
+
+// nonNegative reports a count argument below zero
+func nonNegative(name string, n int) error {
+	if n < 0 {
+		return badRequest(name + " must not be negative")
+	}
+	return nil
+}
+
+// schema builds a JSON Schema for a tool's arguments
+func schema(props map[string]interface{}, required ...string) map[string]interface{} {
+	s := map[string]interface{}{"type": "object", "properties": props}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+func prop(typ, description string) map[string]interface{} {
+	return map[string]interface{}{"type": typ, "description": description}
+}
+
+// mcpTools are the robot operations offered as tools. The ones that change a
+// project work on the project holding the beat they are given, or on the
+// named or first project for new beats and chains, and reload the beats
+// afterwards.
+var mcpTools = []mcpTool{
+	{
+		Name:        "search",
+		Description: "Search beats in every project by content, impetus or ID (--robot-search)",
+		InputSchema: schema(map[string]interface{}{
+			"query":       prop("string", "Text to find"),
+			"max_results": prop("integer", "Most results to return (default 50)"),
+		}, "query"),
+		call: func(s *server, args json.RawMessage) (interface{}, error) {
+			var in struct {
+				Query      string `json:"query"`
+				MaxResults int    `json:"max_results"`
+			}
+			if err := decodeArgs(args, &in); err != nil {
+				return nil, err
+			}
+			if err := required("query", in.Query); err != nil {
+				return nil, err
+			}
+			if err := nonNegative("max_results", in.MaxResults); err != nil {
+				return nil, err
+			}
+			return s.snapshot().search(in.Query, in.MaxResults), nil
+		},
+	},
+	{
+		Name:        "show",
+		Description: "Show a beat in full (--robot-show)",
+		InputSchema: schema(map[string]interface{}{
+			"beat_id": prop("string", "The beat's ID"),
+		}, "beat_id"),
+		call: func(s *server, args json.RawMessage) (interface{}, error) {
+			var in struct {
+				BeatID string `json:"beat_id"`
+			}
+			if err := decodeArgs(args, &in); err != nil {
+				return nil, err
+			}
+			return s.snapshot().show(in.BeatID)
+		},
+	},
+	{
+		Name:        "ripe",
+		Description: "The ripest beats, those most ready to act on (--robot-ripe)",
+		InputSchema: schema(map[string]interface{}{
+			"limit":     prop("integer", "Most beats to return (default 10)"),
+			"threshold": prop("number", "Lowest ripeness to include (default 0)"),
+		}),
+		call: func(s *server, args json.RawMessage) (interface{}, error) {
+			in := struct {
+				Limit     int     `json:"limit"`
+				Threshold float64 `json:"threshold"`
+			}{Limit: 10}
+			if err := decodeArgs(args, &in); err != nil {
+				return nil, err
+			}
+			if err := nonNegative("limit", in.Limit); err != nil {
+				return nil, err
+			}
+			return s.snapshot().ripe(in.Limit, in.Threshold), nil
+		},
+	},
+	{
+		Name:        "stale",
+		Description: "Stale beats with why they are stale and what to do about them (--robot-stale)",
+		InputSchema: schema(map[string]interface{}{}),
+		call: func(s *server, args json.RawMessage) (interface{}, error) {
+			return s.snapshot().stale(), nil
+		},
+	},
+	{
+		Name:        "similar",
+		Description: "Beats closest in meaning to one, through Ollama embeddings (--robot-similar)",
+		InputSchema: schema(map[string]interface{}{
+			"beat_id": prop("string", "The beat to compare with"),
+			"limit":   prop("integer", "Most beats to return (default 5)"),
+		}, "beat_id"),
+		call: func(s *server, args json.RawMessage) (interface{}, error) {
+			in := struct {
+				BeatID string `json:"beat_id"`
+				Limit  int    `json:"limit"`
+			}{Limit: 5}
+			if err := decodeArgs(args, &in); err != nil {
+				return nil, err
+			}
+			if err := nonNegative("limit", in.Limit); err != nil {
+				return nil, err
+			}
+			return s.snapshot().similar(in.BeatID, in.Limit)
+		},
+	},
+	{
+		Name:        "create_chain",
+		Description: "Create a named chain of beats, saved in the cache of the project holding them (--robot-create-chain)",
+		InputSchema: schema(map[string]interface{}{
+			"name":     prop("string", "The chain's name"),
+			"beat_ids": map[string]interface{}{"type": "array", "items": map[string]string{"type": "string"}, "description": "Beats in the chain, in order, all from one project"},
+			"project":  prop("string", "Project to save the chain in (default the beats' project, or the first)"),
+		}, "name"),
+		call: func(s *server, args json.RawMessage) (interface{}, error) {
+			var in struct {
+				Name    string   `json:"name"`
+				BeatIDs []string `json:"beat_ids"`
+				Project string   `json:"project"`
+			}
+			if err := decodeArgs(args, &in); err != nil {
+				return nil, err
+			}
+			if err := required("name", in.Name); err != nil {
+				return nil, err
+			}
+			dir, err := s.chainDir(in.Project, in.BeatIDs)
+			if err != nil {
+				return nil, err
+			}
+			c, err := createChain(dir, in.Name, in.BeatIDs)
+			if err != nil {
+				return nil, err
+			}
+			s.reload()
+			return map[string]interface{}{"chain": c, "message": "Chain created"}, nil
+		},
+	},
+	{
+		Name:        "chain_add",
+		Description: "Add a beat to the end of a chain in the beat's project (--robot-chain-add)",
+		InputSchema: schema(map[string]interface{}{
+			"chain_id": prop("string", "The chain's ID"),
+			"beat_id":  prop("string", "The beat to add"),
+		}, "chain_id", "beat_id"),
+		call: func(s *server, args json.RawMessage) (interface{}, error) {
+			var in struct {
+				ChainID string `json:"chain_id"`
+				BeatID  string `json:"beat_id"`
+			}
+			if err := decodeArgs(args, &in); err != nil {
+				return nil, err
+			}
+			if err := required("chain_id", in.ChainID); err != nil {
+				return nil, err
+			}
+			if err := required("beat_id", in.BeatID); err != nil {
+				return nil, err
+			}
+			dir, err := s.beatDir(in.BeatID)
+			if err != nil {
+				return nil, err
+			}
+			if err := addToChain(dir, in.ChainID, in.BeatID); err != nil {
+				return nil, err
+			}
+			s.reload()
+			return map[string]interface{}{
+				"success":  true,
+				"chain_id": in.ChainID,
+				"beat_id":  in.BeatID,
+				"message":  "Beat added to chain",
+			}, nil
+		},
+	},
+	{
+		Name:        "capture",
+		Description: "Capture a new beat with the beats CLI (bt add) (--capture)",
+		InputSchema: schema(map[string]interface{}{
+			"content": prop("string", "The insight to capture"),
+			"project": prop("string", "Project to capture into (default the first)"),
+		}, "content"),
+		call: func(s *server, args json.RawMessage) (interface{}, error) {
+			var in struct {
+				Content string `json:"content"`
+				Project string `json:"project"`
+			}
+			if err := decodeArgs(args, &in); err != nil {
+				return nil, err
+			}
+			if err := required("content", strings.TrimSpace(in.Content)); err != nil {
+				return nil, err
+			}
+			dir, err := s.projectDir(in.Project)
+			if err != nil {
+				return nil, err
+			}
+			beat, err := capture.Add(dir, in.Content)
+			if err != nil {
+				return nil, err
+			}
+			s.reload()
+			return beat, nil
+		},
+	},
+	{
+		Name:        "reclassify",
+		Description: "Set a beat's channel and/or source by hand, or clear that to go back to the classifier (--robot-reclassify)",
+		InputSchema: schema(map[string]interface{}{
+			"beat_id": prop("string", "The beat's ID"),
+			"channel": prop("string", "Channel to set"),
+			"source":  prop("string", "Source to set"),
+			"clear":   prop("boolean", "Drop the manual classification"),
+		}, "beat_id"),
+		call: func(s *server, args json.RawMessage) (interface{}, error) {
+			var in struct {
+				BeatID  string `json:"beat_id"`
+				Channel string `json:"channel"`
+				Source  string `json:"source"`
+				Clear   bool   `json:"clear"`
+			}
+			if err := decodeArgs(args, &in); err != nil {
+				return nil, err
+			}
+			dir, err := s.beatDir(in.BeatID)
+			if err != nil {
+				return nil, err
+			}
+			resp, err := reclassify(dir, in.BeatID, reclassification{Channel: in.Channel, Source: in.Source, Clear: in.Clear})
+			if err != nil {
+				return nil, err
+			}
+			s.reload()
+			return resp, nil
+		},
+	},
+}
+
+// mcpResources are the collections a client can read
+var mcpResources = []map[string]string{
+	{"uri": "btv://beats", "name": "beats", "description": "Every beat in every project, newest first (--robot-list)", "mimeType": "application/json"},
+	{"uri": "btv://clusters", "name": "clusters", "description": "Theme clusters (--robot-clusters)", "mimeType": "application/json"},
+}
+
+// mcpResourceTemplates address single beats and clusters
+var mcpResourceTemplates = []map[string]string{
+	{"uriTemplate": "btv://beats/{id}", "name": "beat", "description": "A beat in full (--robot-show)", "mimeType": "application/json"},
+	{"uriTemplate": "btv://clusters/{id}", "name": "cluster", "description": "A theme cluster with its beats", "mimeType": "application/json"},
+}
+
+// readResource returns what a btv:// URI names
+func readResource(d *dataset, uri string) (interface{}, error) {
+	path, ok := strings.CutPrefix(uri, "btv://")
+	if !ok {
+		return nil, fmt.Errorf("unknown resource: %s", uri)
+	}
+	kind, id, _ := strings.Cut(path, "/")
+	switch {
+	case kind == "beats" && id == "":
+		return d.list(nil), nil
+	case kind == "beats":
+		return d.show(id)
+	case kind == "clusters" && id == "":
+		return d.clusters(), nil
+	case kind == "clusters":
+		return d.cluster(id)
+	}
+	return nil, errors.New("unknown resource: " + uri)
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bierlingm/beats_viewer/pkg/loader"
+)
+
+// setupServer loads a server over a root holding one project with two beats
+func setupServer(t *testing.T) *server {
+	t.Helper()
+	root := t.TempDir()
+	writeProject(t, root, "notes",
+		`{"id":"beat-1","created_at":"2026-10-01T09:00:00Z","content":"We should ship the importer"}`,
+		`{"id":"beat-2","created_at":"2026-10-02T09:00:00Z","content":"Rest after shipping"}`)
+	return loadServer(t, root)
+}
+
+// writeProject creates a project under root holding the given beats.jsonl
+// lines and returns its .beats directory
+func writeProject(t *testing.T, root, name string, beats ...string) string {
+	t.Helper()
+	beatsDir := filepath.Join(root, name, loader.BeatsDir)
+	if err := os.MkdirAll(beatsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := strings.Join(beats, "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(beatsDir, loader.BeatsFile), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return beatsDir
+}
+
+// loadServer loads a server over root, with config kept out of the home
+// directory
+func loadServer(t *testing.T, root string) *server {
+	t.Helper()
+	t.Setenv("BTV_CONFIG_DIR", t.TempDir())
+	s := &server{rootPath: root}
+	if err := s.load(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	return s
+}
+
+// mcpReply is a response read back from serveMCP
+type mcpReply struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+// callMCP sends each line to serveMCP and returns its replies by request ID
+func callMCP(t *testing.T, s *server, lines ...string) map[string]mcpReply {
+	t.Helper()
+	var out bytes.Buffer
+	if err := serveMCP(s, strings.NewReader(strings.Join(lines, "\n")+"\n"), &out); err != nil {
+		t.Fatalf("serveMCP: %v", err)
+	}
+
+	replies := make(map[string]mcpReply)
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var r mcpReply
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("reply %q: %v", scanner.Text(), err)
+		}
+		replies[string(r.ID)] = r
+	}
+	return replies
+}
+
+func TestServeMCP(t *testing.T) {
+	s := setupServer(t)
+	replies := callMCP(t, s,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"ripe","arguments":{"limit":1}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"search","arguments":{"query":"ship"}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"ping"}`,
+	)
+	if len(replies) != 5 {
+		t.Fatalf("got %d replies, want 5 (none for the notification): %v", len(replies), replies)
+	}
+	for id, r := range replies {
+		if r.Error != nil {
+			t.Errorf("request %s failed: %+v", id, r.Error)
+		}
+	}
+
+	var initialized struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if err := json.Unmarshal(replies["1"].Result, &initialized); err != nil {
+		t.Fatal(err)
+	}
+	if initialized.ProtocolVersion != "2025-03-26" {
+		t.Errorf("protocolVersion = %q, want 2025-03-26", initialized.ProtocolVersion)
+	}
+
+	var listed struct {
+		Tools []mcpTool `json:"tools"`
+	}
+	if err := json.Unmarshal(replies["2"].Result, &listed); err != nil {
+		t.Fatal(err)
+	}
+	if len(listed.Tools) != len(mcpTools) {
+		t.Errorf("listed %d tools, want %d", len(listed.Tools), len(mcpTools))
+	}
+
+	var ripe struct {
+		Count int `json:"count"`
+	}
+	toolOutput(t, replies["3"], &ripe)
+	if ripe.Count != 1 {
+		t.Errorf("ripe count = %d, want 1", ripe.Count)
+	}
+
+	var search struct {
+		TotalMatches int `json:"total_matches"`
+	}
+	toolOutput(t, replies["4"], &search)
+	if search.TotalMatches != 2 {
+		t.Errorf("search matches = %d, want 2", search.TotalMatches)
+	}
+}
+
+func TestServeMCPRejectsBadArguments(t *testing.T) {
+	s := setupServer(t)
+	replies := callMCP(t, s,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"ripe","arguments":{"limit":-1}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"search","arguments":{"query":"ship","max_results":-1}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"similar","arguments":{"beat_id":"beat-1","limit":-1}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"search","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"ripe","arguments":{"limit":"ten"}}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"ping"}`,
+	)
+
+	for _, id := range []string{"1", "2", "3", "4", "5"} {
+		r, ok := replies[id]
+		if !ok {
+			t.Errorf("no reply to request %s", id)
+			continue
+		}
+		if r.Error == nil || r.Error.Code != rpcInvalidParams {
+			t.Errorf("request %s error = %+v, want code %d", id, r.Error, rpcInvalidParams)
+		}
+	}
+	if r := replies["6"]; r.Error != nil || r.Result == nil {
+		t.Errorf("ping after bad arguments = %+v, want a result", r)
+	}
+}
+
+func TestServeMCPChangesBeatsInTheirProject(t *testing.T) {
+	root := t.TempDir()
+	alpha := writeProject(t, root, "alpha",
+		`{"id":"beat-1","created_at":"2026-10-01T09:00:00Z","content":"We should ship the importer"}`)
+	beta := writeProject(t, root, "beta",
+		`{"id":"beat-b1","created_at":"2026-10-03T09:00:00Z","content":"Plan the kickoff"}`,
+		`{"id":"beat-b2","created_at":"2026-10-04T09:00:00Z","content":"Book the room"}`)
+	s := loadServer(t, root)
+
+	replies := callMCP(t, s,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"reclassify","arguments":{"beat_id":"beat-b1","channel":"Coaching"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"create_chain","arguments":{"name":"Kickoff","beat_ids":["beat-b1"]}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"create_chain","arguments":{"name":"Mixed","beat_ids":["beat-1","beat-b1"]}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"capture","arguments":{"content":"Elsewhere","project":"gamma"}}}`,
+	)
+	for _, id := range []string{"1", "2"} {
+		if r := replies[id]; r.Error != nil {
+			t.Errorf("request %s failed: %+v", id, r.Error)
+		}
+	}
+	for _, id := range []string{"3", "4"} {
+		if r := replies[id]; r.Error == nil || r.Error.Code != rpcInvalidParams {
+			t.Errorf("request %s error = %+v, want code %d", id, r.Error, rpcInvalidParams)
+		}
+	}
+
+	var reclassified struct {
+		BeatID string `json:"beat_id"`
+	}
+	toolOutput(t, replies["1"], &reclassified)
+
+	var created struct {
+		Chain struct {
+			ID string `json:"id"`
+		} `json:"chain"`
+	}
+	toolOutput(t, replies["2"], &created)
+	replies = callMCP(t, s,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"chain_add","arguments":{"chain_id":"`+created.Chain.ID+`","beat_id":"beat-b2"}}}`,
+	)
+	var added struct {
+		Success bool `json:"success"`
+	}
+	toolOutput(t, replies["5"], &added)
+	if !added.Success {
+		t.Errorf("chain_add did not succeed")
+	}
+
+	overrides, err := loader.LoadTaxonomyOverrides(beta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := overrides["beat-b1"].Channel; got != "Coaching" {
+		t.Errorf("beta override channel = %q, want Coaching", got)
+	}
+	if overrides, err := loader.LoadTaxonomyOverrides(alpha); err != nil || len(overrides) != 0 {
+		t.Errorf("alpha overrides = %v, %v; want none", overrides, err)
+	}
+
+	cache, err := loader.LoadCache(beta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cache.Chains) != 1 || len(cache.Chains[0].BeatIDs) != 2 {
+		t.Fatalf("beta chains = %+v, want one of beat-b1 and beat-b2", cache.Chains)
+	}
+}
+
+// toolOutput decodes a successful tool call's JSON text into v
+func toolOutput(t *testing.T, r mcpReply, v interface{}) {
+	t.Helper()
+	var result struct {
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+		IsError bool `json:"isError"`
+	}
+	if err := json.Unmarshal(r.Result, &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.IsError || len(result.Content) != 1 {
+		t.Fatalf("tool result = %s, want one JSON text", r.Result)
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), v); err != nil {
+		t.Fatalf("tool output %q: %v", result.Content[0].Text, err)
+	}
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bierlingm/beats_viewer/pkg/loader"
+	"github.com/bierlingm/beats_viewer/pkg/timeline"
+	"github.com/bierlingm/beats_viewer/pkg/watch"
+)
+
+// defaultServeAddr keeps the API on the local machine unless told otherwise
+const defaultServeAddr = "127.0.0.1:7777"
+
+//go:embed openapi.json
+var openAPISpec []byte
+
+// server answers the robot operations over HTTP from beats held in memory,
+// reloading them when a project's beats.jsonl changes
+type server struct {
+	rootPath string
+
+	mu       sync.RWMutex
+	data     *dataset
+	beatsDir string            // the first project's, where new beats go by default
+	dirs     map[string]string // beats directory by project name
+	beatDirs map[string]string // beat ID to its project's beats directory
+	files    []string          // every project's beats.jsonl
+	loadedAt time.Time
+}
+
+func runServe() {
+	addr := defaultServeAddr
+	for i, arg := range os.Args {
+		if arg == "--addr" && i+1 < len(os.Args) {
+			addr = os.Args[i+1]
+		}
+	}
+
+	s := &server{rootPath: rootArg()}
+	if err := s.load(); err != nil {
+		fatal(err.Error())
+	}
+	go s.watch()
+
+	fmt.Fprintf(os.Stderr, "Serving %d beats on http://%s (OpenAPI at /openapi.json)\n", len(s.snapshot().beats), addr)
+	if err := http.ListenAndServe(addr, s.routes()); err != nil {
+		fatal(err.Error())
+	}
+}
+
+// rootArg returns the root to discover projects under: --root, then
+// BEATS_ROOT, then the default
+func rootArg() string {
+	rootPath := loader.GetDefaultRoot()
+	if envRoot := os.Getenv("BEATS_ROOT"); envRoot != "" {
+		rootPath = envRoot
+	}
+	for i, arg := range os.Args {
+		if arg == "--root" && i+1 < len(os.Args) {
+			rootPath = os.Args[i+1]
+		}
+	}
+	return rootPath
+}
+
+// load reads and enriches every project's beats, each with its own cache and
+// ripeness profile, and swaps them in
+func (s *server) load() error {
+	projects, err := loader.DiscoverProjects(s.rootPath)
+	if err != nil || len(projects) == 0 {
+		return fmt.Errorf("no projects found")
+	}
+	beats, beatToProject, err := loader.LoadAllBeats(s.rootPath)
+	if err != nil {
+		return err
+	}
+	data := make([]projectData, len(projects))
+	files := make([]string, len(projects))
+	dirs := make(map[string]string, len(projects))
+	beatDirs := make(map[string]string, len(beats))
+	for i, p := range projects {
+		if data[i], err = loadProjectData(p.Path); err != nil {
+			return fmt.Errorf("loading %s: %w", p.Name, err)
+		}
+		files[i] = filepath.Join(p.Path, loader.BeatsFile)
+		if _, ok := dirs[p.Name]; !ok {
+			dirs[p.Name] = p.Path
+		}
+		for _, eb := range data[i].enriched {
+			beatDirs[eb.ID] = p.Path
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = &dataset{
+		beats:         beats,
+		beatToProject: beatToProject,
+		projects:      data,
+	}
+	s.beatsDir = projects[0].Path
+	s.dirs = dirs
+	s.beatDirs = beatDirs
+	s.files = files
+	s.loadedAt = time.Now()
+	return nil
+}
+
+// snapshot returns the current data, which is replaced rather than changed
+// on reload, so it can be read without holding the lock
+func (s *server) snapshot() *dataset {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.data
+}
+
+func (s *server) beatsFiles() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.files
+}
+
+// watch reloads whenever a beats file changes, watching new projects as
+// they are discovered
+func (s *server) watch() {
+	files := s.beatsFiles()
+	w := watch.New(files)
+	for w.Next() != nil {
+		if err := s.load(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reloading: %v\n", err)
+			continue
+		}
+		if current := s.beatsFiles(); !slices.Equal(current, files) {
+			w.Close()
+			files = current
+			w = watch.New(files)
+		}
+	}
+}
+
+// badRequest reports a query parameter that can't be used
+type badRequest string
+
+func (e badRequest) Error() string { return string(e) }
+
+// handler answers a request from the current data
+type handler func(d *dataset, r *http.Request) (interface{}, error)
+
+func (s *server) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	handle := func(pattern string, h handler) {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			resp, err := h(s.snapshot(), r)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, resp)
+		})
+	}
+
+	handle("GET /beats", func(d *dataset, r *http.Request) (interface{}, error) {
+		var project *string
+		if p := r.URL.Query().Get("project"); p != "" {
+			project = &p
+		}
+		return d.list(project), nil
+	})
+	handle("GET /beats/{id}", func(d *dataset, r *http.Request) (interface{}, error) {
+		return d.show(r.PathValue("id"))
+	})
+	handle("GET /beats/{id}/ripeness", func(d *dataset, r *http.Request) (interface{}, error) {
+		return d.ripeness(r.PathValue("id"))
+	})
+	handle("GET /search", func(d *dataset, r *http.Request) (interface{}, error) {
+		max, err := intParam(r, "max", 0)
+		if err != nil {
+			return nil, err
+		}
+		return d.search(r.URL.Query().Get("q"), max), nil
+	})
+	handle("GET /ripe", func(d *dataset, r *http.Request) (interface{}, error) {
+		limit, err := intParam(r, "limit", 10)
+		if err != nil {
+			return nil, err
+		}
+		threshold := 0.0
+		if v := r.URL.Query().Get("threshold"); v != "" {
+			if threshold, err = strconv.ParseFloat(v, 64); err != nil {
+				return nil, badRequest("threshold must be a number")
+			}
+		}
+		return d.ripe(limit, threshold), nil
+	})
+	handle("GET /entities", func(d *dataset, r *http.Request) (interface{}, error) {
+		return d.entities(), nil
+	})
+	handle("GET /timeline", func(d *dataset, r *http.Request) (interface{}, error) {
+		zoom := timeline.ZoomMonth
+		if v := r.URL.Query().Get("zoom"); v != "" {
+			z, ok := timeline.ParseZoomLevel(v)
+			if !ok {
+				return nil, badRequest("unknown zoom level: " + v)
+			}
+			zoom = z
+		}
+		return d.timeline(zoom), nil
+	})
+	handle("GET /clusters", func(d *dataset, r *http.Request) (interface{}, error) {
+		return d.clusters(), nil
+	})
+	handle("GET /chains", func(d *dataset, r *http.Request) (interface{}, error) {
+		return d.chains(), nil
+	})
+	handle("GET /stale", func(d *dataset, r *http.Request) (interface{}, error) {
+		return d.stale(), nil
+	})
+
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.RLock()
+		resp := map[string]interface{}{
+			"status":    "ok",
+			"beats":     len(s.data.beats),
+			"projects":  len(s.files),
+			"loaded_at": s.loadedAt,
+		}
+		s.mu.RUnlock()
+		writeJSON(w, http.StatusOK, resp)
+	})
+	mux.HandleFunc("POST /reload", func(w http.ResponseWriter, r *http.Request) {
+		if err := s.load(); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"status": "reloaded", "beats": len(s.snapshot().beats)})
+	})
+	mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openAPISpec)
+	})
+	return mux
+}
+
+func intParam(r *http.Request, name string, def int) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, badRequest(name + " must be an integer")
+	}
+	if n < 0 {
+		return 0, badRequest(name + " must not be negative")
+	}
+	return n, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		fmt.Fprintf(os.Stderr, "JSON encoding error: %v\n", err)
+	}
+}
+
+// writeError answers with {"error": ...} and a status matching the error
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var notFound errNotFound
+	var bad badRequest
+	switch {
+	case errors.As(err, &notFound):
+		status = http.StatusNotFound
+	case errors.As(err, &bad):
+		status = http.StatusBadRequest
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,285 @@
+package bead
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats_viewer/pkg/config"
+	"github.com/bierlingm/beats_viewer/pkg/loader"
+	"github.com/bierlingm/beats_viewer/pkg/model"
+)
+
+// ConfigSection is the config file section configuring bd
+const ConfigSection = "beads"
+
+// Config says how to run bd and where to record links
+type Config struct {
+	Command        string `json:"command,omitempty"`         // bd executable, a path or a name on PATH
+	WriteBeats     bool   `json:"write_beats,omitempty"`     // also add links to beats.jsonl
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty"` // per bd call
+	EpicChildren   bool   `json:"epic_children,omitempty"`   // give ripe members of an epic their own child beads
+}
+
+// DefaultConfig returns the bd settings used when config files leave them out
+func DefaultConfig() Config {
+	return Config{Command: "bd", TimeoutSeconds: 30}
+}
+
+// LoadConfig overlays the global and project bd settings on the defaults and
+// returns the files that contributed
+func LoadConfig(beatsDir string) (Config, []string, error) {
+	cfg := DefaultConfig()
+	sources, err := config.LoadSection(beatsDir, ConfigSection, &cfg)
+	if err != nil {
+		return DefaultConfig(), sources, err
+	}
+	if cfg.Command == "" {
+		cfg.Command = DefaultConfig().Command
+	}
+	if cfg.TimeoutSeconds <= 0 {
+		cfg.TimeoutSeconds = DefaultConfig().TimeoutSeconds
+	}
+	return cfg, sources, nil
+}
+
+// Client runs bd for one project
+type Client struct {
+	cfg Config
+	dir string // project root, where bd looks for .beads/
+}
+
+// NewClient returns a client running bd from the project holding beatsDir
+func NewClient(cfg Config, beatsDir string) *Client {
+	return &Client{cfg: cfg, dir: filepath.Dir(beatsDir)}
+}
+
+// Issue is a bead to create
+type Issue struct {
+	Title       string
+	Description string
+	Type        string // bd issue type, e.g. epic; bd's default when empty
+	Parent      string // ID of the epic this bead belongs under
+}
+
+// maxTitleLength keeps bead titles to one readable line
+const maxTitleLength = 80
+
+// IssueForBeat proposes a bead for a beat: its first line as the title and
+// the whole beat, with where it came from, as the description
+func IssueForBeat(beat model.Beat) Issue {
+	title := titleLine(beat.Content)
+	if title == "" {
+		title = "Beat: " + beat.ID
+	}
+
+	description := fmt.Sprintf("%s\n\nFrom beat %s (%s, %s)",
+		strings.TrimSpace(beat.Content), beat.ID, beat.ImpetusLabel(), beat.CreatedAt.Format("2006-01-02"))
+	return Issue{Title: title, Description: description}
+}
+
+// titleLine cuts text to its first line, at most maxTitleLength runes
+func titleLine(text string) string {
+	title := strings.TrimSpace(text)
+	if i := strings.IndexByte(title, '\n'); i >= 0 {
+		title = strings.TrimSpace(title[:i])
+	}
+	if runes := []rune(title); len(runes) > maxTitleLength {
+		title = strings.TrimSpace(string(runes[:maxTitleLength-3])) + "..."
+	}
+	return title
+}
+
+// CreateArgs returns the command line that creates an issue. The title and
+// description are passed as flag values, since beats often start with "-"
+// and bd would read them as flags.
+func (c *Client) CreateArgs(issue Issue) []string {
+	args := []string{c.cfg.Command, "create", "--title", issue.Title, "--description", issue.Description}
+	if issue.Type != "" {
+		args = append(args, "-t", issue.Type)
+	}
+	if issue.Parent != "" {
+		args = append(args, "--parent", issue.Parent)
+	}
+	return append(args, "--json")
+}
+
+// Create runs bd create and returns the ID of the new bead
+func (c *Client) Create(issue Issue) (string, error) {
+	out, err := c.run(c.CreateArgs(issue)[1:]...)
+	if err != nil {
+		return "", err
+	}
+	id, err := parseCreatedID(out)
+	if err != nil {
+		return "", fmt.Errorf("reading bd create output: %w", err)
+	}
+	return id, nil
+}
+
+// Show returns what bd knows about a bead
+func (c *Client) Show(id string) (model.BeadStatus, error) {
+	out, err := c.run("show", id, "--json")
+	if err != nil {
+		return model.BeadStatus{}, err
+	}
+	status, err := parseStatus(out)
+	if err != nil {
+		return model.BeadStatus{}, fmt.Errorf("reading bd show output for %s: %w", id, err)
+	}
+	return status, nil
+}
+
+func (c *Client) run(args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(c.cfg.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, c.cfg.Command, args...)
+	cmd.Dir = c.dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("running %s %s: %w: %s", c.cfg.Command, args[0], err, msg)
+		}
+		return nil, fmt.Errorf("running %s %s: %w", c.cfg.Command, args[0], err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// createdIDPattern finds the ID in bd's human-readable output, for versions
+// that ignore --json
+var createdIDPattern = regexp.MustCompile(`(?i)created issue:?\s+([A-Za-z0-9][\w.-]*-[\w.]+)`)
+
+func parseCreatedID(out []byte) (string, error) {
+	if status, err := parseStatus(out); err == nil && status.ID != "" {
+		return status.ID, nil
+	}
+	if m := createdIDPattern.FindSubmatch(out); m != nil {
+		return string(m[1]), nil
+	}
+	return "", fmt.Errorf("no issue ID in %q", strings.TrimSpace(string(out)))
+}
+
+// parseStatus reads an issue from bd's JSON, which is an object or, from
+// some commands, an array holding one
+func parseStatus(out []byte) (model.BeadStatus, error) {
+	out = bytes.TrimSpace(out)
+	var status model.BeadStatus
+	if bytes.HasPrefix(out, []byte("[")) {
+		var list []model.BeadStatus
+		if err := json.Unmarshal(out, &list); err != nil {
+			return status, err
+		}
+		if len(list) == 0 {
+			return status, fmt.Errorf("empty issue list")
+		}
+		return list[0], nil
+	}
+	if err := json.Unmarshal(out, &status); err != nil {
+		return status, err
+	}
+	return status, nil
+}
+
+// CreateForBeat creates a bead for a beat and records the link under
+// .beats/, and in beats.jsonl when configured to. It returns the bead ID.
+func CreateForBeat(beatsDir string, beat model.Beat) (string, error) {
+	cfg, _, err := LoadConfig(beatsDir)
+	if err != nil {
+		return "", err
+	}
+	id, err := NewClient(cfg, beatsDir).Create(IssueForBeat(beat))
+	if err != nil {
+		return "", err
+	}
+	if err := Link(beatsDir, cfg, beat.ID, id); err != nil {
+		return id, err
+	}
+	return id, nil
+}
+
+// Link records that a bead belongs to a beat
+func Link(beatsDir string, cfg Config, beatID, beadID string) error {
+	return LinkAll(beatsDir, cfg, []model.BeadLink{{BeatID: beatID, BeadID: beadID}})
+}
+
+// LinkAll records links between beats and beads in the project's link log
+// and, when configured to, in beats.jsonl with a single rewrite
+func LinkAll(beatsDir string, cfg Config, links []model.BeadLink) error {
+	now := time.Now()
+	for _, link := range links {
+		link.At = now
+		if err := loader.AppendBeadLink(beatsDir, link); err != nil {
+			return err
+		}
+	}
+	if cfg.WriteBeats {
+		if err := loader.WriteBeadLinks(beatsDir, links); err != nil {
+			return fmt.Errorf("recording links in beats.jsonl: %w", err)
+		}
+	}
+	return nil
+}
+
+// Statuses looks up each bead in the beads project's data, asking bd about
+// those it doesn't hold and leaving out those bd can't show either
+func Statuses(beatsDir string, ids []string) (map[string]model.BeadStatus, error) {
+	issues, err := loader.LoadProjectBeadIssues(beatsDir)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make(map[string]model.BeadStatus, len(ids))
+	var missing []string
+	for _, id := range ids {
+		if status, ok := issues[id]; ok {
+			statuses[id] = status
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return statuses, nil
+	}
+
+	cfg, _, err := LoadConfig(beatsDir)
+	if err != nil {
+		return nil, err
+	}
+	client := NewClient(cfg, beatsDir)
+	var firstErr error
+	for _, id := range missing {
+		status, err := client.Show(id)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		statuses[id] = status
+	}
+	if len(statuses) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+	return statuses, nil
+}
+
+// QuoteArgs renders a command line for display, quoting arguments the shell
+// would split
+func QuoteArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n'\"\\$`!*?&;|<>()[]{}#~") {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,213 @@
+package bead
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats_viewer/pkg/loader"
+	"github.com/bierlingm/beats_viewer/pkg/model"
+)
+
+// fakeBD answers bd create with a new bead and bd show with its status,
+// logging the directory each call ran in, its subcommand and the title or ID
+const fakeBD = `#!/bin/sh
+case "$1" in
+create)
+	[ "$2" = "--title" ] || { echo "bd create without --title: $2" >&2; exit 1; }
+	echo "$(pwd) $1 $3" >> "$BD_LOG"
+	echo '{"id":"bd-42","title":"'"$3"'","status":"open"}' ;;
+show)
+	echo "$(pwd) $1 $2" >> "$BD_LOG"
+	echo '[{"id":"'"$2"'","title":"Ship it","status":"in_progress","priority":1}]' ;;
+*) echo "unexpected bd $1" >&2; exit 1 ;;
+esac
+`
+
+// setupProject creates a project holding one beat, with a fake bd first on
+// PATH, and returns its .beats directory and the bd call log
+func setupProject(t *testing.T, projectConfig string) (string, string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake bd is a shell script")
+	}
+
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(fakeBD), 0755); err != nil {
+		t.Fatal(err)
+	}
+	logPath := filepath.Join(t.TempDir(), "bd.log")
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("BD_LOG", logPath)
+	t.Setenv("BTV_CONFIG_DIR", t.TempDir())
+
+	beatsDir := filepath.Join(t.TempDir(), loader.BeatsDir)
+	if err := os.Mkdir(beatsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	beats := `{"id":"beat-1","created_at":"2026-10-01T09:00:00Z","content":"Ship it\nthen rest","custom":true}` + "\n"
+	if err := os.WriteFile(filepath.Join(beatsDir, loader.BeatsFile), []byte(beats), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if projectConfig != "" {
+		if err := os.WriteFile(filepath.Join(beatsDir, "btv-config.json"), []byte(projectConfig), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return beatsDir, logPath
+}
+
+func TestCreateForBeatLinksAndReadsStatus(t *testing.T) {
+	beatsDir, logPath := setupProject(t, "")
+	beat := model.Beat{ID: "beat-1", Content: "Ship it", CreatedAt: time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)}
+
+	id, err := CreateForBeat(beatsDir, beat)
+	if err != nil {
+		t.Fatalf("CreateForBeat: %v", err)
+	}
+	if id != "bd-42" {
+		t.Fatalf("bead ID = %q, want bd-42", id)
+	}
+
+	links, err := loader.LoadBeadLinks(beatsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := links["beat-1"]; len(got) != 1 || got[0] != "bd-42" {
+		t.Errorf("links for beat-1 = %v, want [bd-42]", got)
+	}
+	beats, err := loader.LoadBeats(beatsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(beats) != 1 || len(beats[0].LinkedBeads) != 1 || beats[0].LinkedBeads[0] != "bd-42" {
+		t.Errorf("loaded beats = %+v, want beat-1 linked to bd-42", beats)
+	}
+	data, err := os.ReadFile(filepath.Join(beatsDir, loader.BeatsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "linked_beads") {
+		t.Errorf("beats.jsonl was written without write_beats: %s", data)
+	}
+
+	statuses, err := Statuses(beatsDir, []string{"bd-42"})
+	if err != nil {
+		t.Fatalf("Statuses: %v", err)
+	}
+	want := model.BeadStatus{ID: "bd-42", Title: "Ship it", Status: "in_progress", Priority: 1}
+	if statuses["bd-42"] != want {
+		t.Errorf("status = %+v, want %+v", statuses["bd-42"], want)
+	}
+
+	log, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := strings.Split(strings.TrimSpace(string(log)), "\n")
+	if len(calls) != 2 {
+		t.Fatalf("bd calls = %q, want create and show", calls)
+	}
+	root := filepath.Dir(beatsDir)
+	if calls[0] != root+" create Ship it" {
+		t.Errorf("create call = %q", calls[0])
+	}
+	if calls[1] != root+" show bd-42" {
+		t.Errorf("show call = %q", calls[1])
+	}
+}
+
+func TestCreateForBeatWithDashTitle(t *testing.T) {
+	beatsDir, logPath := setupProject(t, "")
+	beat := model.Beat{ID: "beat-1", Content: "- ship it\n- then rest"}
+
+	args := NewClient(DefaultConfig(), beatsDir).CreateArgs(IssueForBeat(beat))
+	if got, want := QuoteArgs(args[:4]), "bd create --title '- ship it'"; got != want {
+		t.Errorf("command = %q, want %q", got, want)
+	}
+
+	id, err := CreateForBeat(beatsDir, beat)
+	if err != nil {
+		t.Fatalf("CreateForBeat: %v", err)
+	}
+	if id != "bd-42" {
+		t.Fatalf("bead ID = %q, want bd-42", id)
+	}
+	log, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.TrimSpace(string(log)), filepath.Dir(beatsDir)+" create - ship it"; got != want {
+		t.Errorf("create call = %q, want %q", got, want)
+	}
+}
+
+func TestCreateForBeatWritesBeats(t *testing.T) {
+	beatsDir, _ := setupProject(t, `{"beads": {"write_beats": true}}`)
+
+	if _, err := CreateForBeat(beatsDir, model.Beat{ID: "beat-1", Content: "Ship it"}); err != nil {
+		t.Fatalf("CreateForBeat: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(beatsDir, loader.BeatsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"id":"beat-1","created_at":"2026-10-01T09:00:00Z","content":"Ship it\nthen rest","custom":true,"linked_beads":["bd-42"]}` + "\n"
+	if string(data) != want {
+		t.Errorf("beats.jsonl =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestCreateEpicLinksEveryMember(t *testing.T) {
+	beatsDir, logPath := setupProject(t, `{"beads": {"write_beats": true}}`)
+	path := filepath.Join(beatsDir, loader.BeatsFile)
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString(`{"id":"beat-2","created_at":"2026-10-02T09:00:00Z","content":"Rest"}` + "\n"); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	g := Group{Kind: "chain", ID: "chain-1", Name: "Shipping", Beats: []model.EnrichedBeat{
+		{Beat: model.Beat{ID: "beat-1", Content: "Ship it"}},
+		{Beat: model.Beat{ID: "beat-2", Content: "Rest"}},
+	}}
+	result, err := CreateEpic(beatsDir, PlanEpic(g, false), g, nil)
+	if err != nil {
+		t.Fatalf("CreateEpic: %v", err)
+	}
+	if result.EpicID != "bd-42" {
+		t.Fatalf("epic ID = %q, want bd-42", result.EpicID)
+	}
+
+	beats, err := loader.LoadBeats(beatsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range beats {
+		if len(b.LinkedBeads) != 1 || b.LinkedBeads[0] != "bd-42" {
+			t.Errorf("%s linked to %v, want [bd-42]", b.ID, b.LinkedBeads)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), `"linked_beads":["bd-42"]`); n != 2 {
+		t.Errorf("beats.jsonl links %d beats, want 2:\n%s", n, data)
+	}
+
+	log, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls := strings.Split(strings.TrimSpace(string(log)), "\n"); len(calls) != 1 {
+		t.Errorf("bd calls = %q, want one create", calls)
+	}
+}
@@ -0,0 +1,214 @@
+package bead
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bierlingm/beats_viewer/pkg/model"
+)
+
+// maxEpicEntities caps the entities named in an epic's description
+const maxEpicEntities = 8
+
+// Group is a cluster or chain of beats to turn into an epic bead
+type Group struct {
+	Kind     string // "cluster" or "chain"
+	ID       string
+	Name     string
+	Keywords []string
+	Beats    []model.EnrichedBeat // members found among the loaded beats, in group order
+}
+
+// ClusterGroup gathers a cluster's members from beats
+func ClusterGroup(c model.Cluster, beats []model.EnrichedBeat) Group {
+	return Group{Kind: "cluster", ID: c.ID, Name: c.Name, Keywords: c.Keywords, Beats: members(c.BeatIDs, beats)}
+}
+
+// ChainGroup gathers a chain's members from beats, in chain order
+func ChainGroup(c model.Chain, beats []model.EnrichedBeat) Group {
+	return Group{Kind: "chain", ID: c.ID, Name: c.Name, Beats: members(c.BeatIDs, beats)}
+}
+
+func members(ids []string, beats []model.EnrichedBeat) []model.EnrichedBeat {
+	byID := make(map[string]model.EnrichedBeat, len(beats))
+	for _, eb := range beats {
+		byID[eb.ID] = eb
+	}
+	var found []model.EnrichedBeat
+	for _, id := range ids {
+		if eb, ok := byID[id]; ok {
+			found = append(found, eb)
+		}
+	}
+	return found
+}
+
+// ChildIssue is a child bead proposed for one member of an epic
+type ChildIssue struct {
+	BeatID string
+	Issue  Issue
+}
+
+// EpicPlan is the epic bead for a group and, optionally, its children
+type EpicPlan struct {
+	Epic     Issue
+	Children []ChildIssue
+}
+
+// PlanEpic proposes an epic for a group, described by its members' previews,
+// keywords and entities, with a child bead for each ripe member when
+// withChildren is set
+func PlanEpic(g Group, withChildren bool) EpicPlan {
+	title := titleLine(g.Name)
+	if title == "" {
+		title = "Epic: " + g.ID
+	}
+	plan := EpicPlan{Epic: Issue{Title: title, Description: epicDescription(g), Type: "epic"}}
+	if !withChildren {
+		return plan
+	}
+	for _, eb := range g.Beats {
+		if IsRipe(eb) {
+			plan.Children = append(plan.Children, ChildIssue{BeatID: eb.ID, Issue: IssueForBeat(eb.Beat)})
+		}
+	}
+	return plan
+}
+
+// EpicIDPlaceholder stands in for the epic's ID in child commands shown
+// before the epic exists
+const EpicIDPlaceholder = "{epic_id}"
+
+// IsRipe reports whether a beat is ready for a bead of its own
+func IsRipe(eb model.EnrichedBeat) bool {
+	return eb.RipenessState == model.StateRipe || eb.RipenessState == model.StateOverripe
+}
+
+func epicDescription(g Group) string {
+	var sb strings.Builder
+	if g.Name != "" {
+		fmt.Fprintf(&sb, "%d beats from the %s %q.\n", len(g.Beats), g.Kind, g.Name)
+	} else {
+		fmt.Fprintf(&sb, "%d beats from %s %s.\n", len(g.Beats), g.Kind, g.ID)
+	}
+	if len(g.Keywords) > 0 {
+		fmt.Fprintf(&sb, "\nKeywords: %s\n", strings.Join(g.Keywords, ", "))
+	}
+	if entities := topEntities(g.Beats, maxEpicEntities); len(entities) > 0 {
+		fmt.Fprintf(&sb, "\nEntities: %s\n", strings.Join(entities, ", "))
+	}
+	sb.WriteString("\nBeats:\n")
+	for _, eb := range g.Beats {
+		fmt.Fprintf(&sb, "- %s (%s, %s): %s\n",
+			eb.ID, eb.ImpetusLabel(), eb.CreatedAt.Format("2006-01-02"), eb.ContentPreview(100))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// topEntities names the entities mentioned by the most beats, most first
+func topEntities(beats []model.EnrichedBeat, limit int) []string {
+	counts := make(map[string]int)
+	for _, eb := range beats {
+		seen := make(map[string]bool)
+		for _, m := range eb.Mentions {
+			if !seen[m.Name] {
+				seen[m.Name] = true
+				counts[m.Name]++
+			}
+		}
+	}
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > limit {
+		names = names[:limit]
+	}
+	return names
+}
+
+// ChildBead is a child bead created for a member beat
+type ChildBead struct {
+	BeatID string `json:"beat_id"`
+	BeadID string `json:"bead_id"`
+}
+
+// EpicResult is what CreateEpic created
+type EpicResult struct {
+	EpicID   string      `json:"epic_id"`
+	Children []ChildBead `json:"children"`
+}
+
+// CreateEpic creates the epic bead for a group with bd in beatsDir's
+// project, then creates the planned children under it, and links every
+// member beat to the epic and each child to its beat. projectOf gives a
+// member's beats directory when members span projects; nil means they are
+// all in beatsDir. On error it returns, and links, what was created so far.
+func CreateEpic(beatsDir string, plan EpicPlan, g Group, projectOf func(beatID string) string) (EpicResult, error) {
+	result := EpicResult{Children: []ChildBead{}}
+	cfg, _, err := LoadConfig(beatsDir)
+	if err != nil {
+		return result, err
+	}
+	client := NewClient(cfg, beatsDir)
+
+	if result.EpicID, err = client.Create(plan.Epic); err != nil {
+		return result, err
+	}
+	var links []model.BeadLink
+	for _, eb := range g.Beats {
+		links = append(links, model.BeadLink{BeatID: eb.ID, BeadID: result.EpicID})
+	}
+
+	for _, child := range plan.Children {
+		issue := child.Issue
+		issue.Parent = result.EpicID
+		id, err := client.Create(issue)
+		if err != nil {
+			err = fmt.Errorf("creating child bead for %s: %w", child.BeatID, err)
+			if linkErr := linkByProject(beatsDir, projectOf, links); linkErr != nil {
+				return result, linkErr
+			}
+			return result, err
+		}
+		result.Children = append(result.Children, ChildBead{BeatID: child.BeatID, BeadID: id})
+		links = append(links, model.BeadLink{BeatID: child.BeatID, BeadID: id})
+	}
+	return result, linkByProject(beatsDir, projectOf, links)
+}
+
+// linkByProject records links in each beat's own project, one batch per
+// project
+func linkByProject(beatsDir string, projectOf func(beatID string) string, links []model.BeadLink) error {
+	byDir := make(map[string][]model.BeadLink)
+	var dirs []string
+	for _, link := range links {
+		dir := beatsDir
+		if projectOf != nil {
+			if d := projectOf(link.BeatID); d != "" {
+				dir = d
+			}
+		}
+		if _, ok := byDir[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		byDir[dir] = append(byDir[dir], link)
+	}
+	for _, dir := range dirs {
+		cfg, _, err := LoadConfig(dir)
+		if err != nil {
+			return err
+		}
+		if err := LinkAll(dir, cfg, byDir[dir]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
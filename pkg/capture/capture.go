@@ -0,0 +1,93 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats_viewer/pkg/config"
+	"github.com/bierlingm/beats_viewer/pkg/loader"
+	"github.com/bierlingm/beats_viewer/pkg/model"
+)
+
+// ConfigSection is the config file section configuring the beats CLI
+const ConfigSection = "capture"
+
+// Config says how to run the beats CLI, which owns beats.jsonl
+type Config struct {
+	Command        string `json:"command,omitempty"`         // beats executable, a path or a name on PATH
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty"` // per call
+}
+
+// DefaultConfig returns the capture settings used when config files leave
+// them out
+func DefaultConfig() Config {
+	return Config{Command: "bt", TimeoutSeconds: 30}
+}
+
+// LoadConfig overlays the global and project capture settings on the defaults
+// and returns the files that contributed
+func LoadConfig(beatsDir string) (Config, []string, error) {
+	cfg := DefaultConfig()
+	sources, err := config.LoadSection(beatsDir, ConfigSection, &cfg)
+	if err != nil {
+		return DefaultConfig(), sources, err
+	}
+	if cfg.Command == "" {
+		cfg.Command = DefaultConfig().Command
+	}
+	if cfg.TimeoutSeconds <= 0 {
+		cfg.TimeoutSeconds = DefaultConfig().TimeoutSeconds
+	}
+	return cfg, sources, nil
+}
+
+// Add captures a beat with `bt add` from the project holding beatsDir and
+// returns the beat it wrote
+func Add(beatsDir, content string) (*model.Beat, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, fmt.Errorf("nothing to capture")
+	}
+	cfg, _, err := LoadConfig(beatsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := loader.LoadBeats(beatsDir)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(before))
+	for _, b := range before {
+		known[b.ID] = true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.TimeoutSeconds)*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, cfg.Command, "add", content)
+	cmd.Dir = filepath.Dir(beatsDir)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("running %s add: %w: %s", cfg.Command, err, msg)
+		}
+		return nil, fmt.Errorf("running %s add: %w", cfg.Command, err)
+	}
+
+	after, err := loader.LoadBeats(beatsDir)
+	if err != nil {
+		return nil, err
+	}
+	for i := range after {
+		if !known[after[i].ID] {
+			return &after[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%s add did not write a beat to %s", cfg.Command, filepath.Join(beatsDir, loader.BeatsFile))
+}
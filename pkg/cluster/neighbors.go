@@ -0,0 +1,83 @@
+package cluster
+
+import (
+	"math"
+	"sort"
+
+	"github.com/bierlingm/beats_viewer/pkg/model"
+)
+
+// DefaultNeighborCount is how many nearest neighbours are kept per beat
+const DefaultNeighborCount = 5
+
+// NearestNeighbors returns, for every embedded beat, its k most similar
+// other beats by cosine similarity
+func NearestNeighbors(embeddings map[string][]float64, k int) map[string][]model.Neighbor {
+	if k <= 0 {
+		k = DefaultNeighborCount
+	}
+
+	ids := make([]string, 0, len(embeddings))
+	for id := range embeddings {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	unit := make([][]float64, len(ids))
+	for i, id := range ids {
+		unit[i] = normalize(embeddings[id])
+	}
+
+	result := make(map[string][]model.Neighbor, len(ids))
+	for i, id := range ids {
+		var candidates []model.Neighbor
+		for j, otherID := range ids {
+			if i == j || len(unit[i]) != len(unit[j]) {
+				continue
+			}
+			candidates = append(candidates, model.Neighbor{
+				BeatID:     otherID,
+				Similarity: dot(unit[i], unit[j]),
+			})
+		}
+
+		sort.Slice(candidates, func(a, b int) bool {
+			return candidates[a].Similarity > candidates[b].Similarity
+		})
+		if len(candidates) > k {
+			candidates = candidates[:k]
+		}
+		result[id] = candidates
+	}
+
+	return result
+}
+
+// Neighbors computes nearest neighbours over all embeddings fetched so far
+func (e *Engine) Neighbors(k int) map[string][]model.Neighbor {
+	return NearestNeighbors(e.embeddingCache, k)
+}
+
+func normalize(v []float64) []float64 {
+	var norm float64
+	for _, x := range v {
+		norm += x * x
+	}
+	norm = math.Sqrt(norm)
+	out := make([]float64, len(v))
+	if norm == 0 {
+		return out
+	}
+	for i, x := range v {
+		out[i] = x / norm
+	}
+	return out
+}
+
+func dot(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
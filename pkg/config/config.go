@@ -0,0 +1,125 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	FileName        = "config.json"     // in the global config directory
+	ProjectFileName = "btv-config.json" // alongside beats.jsonl in .beats/
+	EnvConfigDir    = "BTV_CONFIG_DIR"
+)
+
+// GlobalDir returns the directory holding user-wide btv configuration
+func GlobalDir() string {
+	if dir := os.Getenv(EnvConfigDir); dir != "" {
+		return dir
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(base, "btv")
+}
+
+// Paths returns the config files consulted for a project, lowest precedence first
+func Paths(beatsDir string) []string {
+	var paths []string
+	if dir := GlobalDir(); dir != "" {
+		paths = append(paths, filepath.Join(dir, FileName))
+	}
+	if beatsDir != "" {
+		paths = append(paths, filepath.Join(beatsDir, ProjectFileName))
+	}
+	return paths
+}
+
+// LoadSection decodes the named top-level section of each config file onto v.
+// Files are applied global first, then project, so project values override
+// global ones field by field. It returns the files that contributed.
+func LoadSection(beatsDir, section string, v interface{}) ([]string, error) {
+	return EachSection(beatsDir, section, func(path string, raw json.RawMessage) error {
+		if err := json.Unmarshal(raw, v); err != nil {
+			return fmt.Errorf("decoding %s in %s: %w", section, path, err)
+		}
+		return nil
+	})
+}
+
+// EachSection calls fn with the raw named section of each config file that
+// has one, global first. Use it when sections should be combined rather than
+// overlaid. It returns the files that contributed.
+func EachSection(beatsDir, section string, fn func(path string, raw json.RawMessage) error) ([]string, error) {
+	var sources []string
+
+	for _, path := range Paths(beatsDir) {
+		raw, err := readSection(path, section)
+		if err != nil {
+			return sources, err
+		}
+		if raw == nil {
+			continue
+		}
+		if err := fn(path, raw); err != nil {
+			return sources, err
+		}
+		sources = append(sources, path)
+	}
+
+	return sources, nil
+}
+
+// SaveSection writes v as the named section of the project config file,
+// preserving any other sections already present
+func SaveSection(beatsDir, section string, v interface{}) error {
+	path := filepath.Join(beatsDir, ProjectFileName)
+
+	sections := make(map[string]json.RawMessage)
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &sections); err != nil {
+			return fmt.Errorf("decoding %s: %w", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshaling %s: %w", section, err)
+	}
+	sections[section] = raw
+
+	data, err := json.MarshalIndent(sections, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling config: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("writing temp config: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("renaming config: %w", err)
+	}
+	return nil
+}
+
+func readSection(path, section string) (json.RawMessage, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return sections[section], nil
+}
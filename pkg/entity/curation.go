@@ -0,0 +1,130 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bierlingm/beats_viewer/pkg/model"
+)
+
+// CurationFileName stores entity curation alongside beats.jsonl. It uses the
+// dictionary config format and is applied after the config files, so curation
+// wins over both.
+const CurationFileName = "btv-entities.json"
+
+// MergeEntity makes from an alias of into, listing into under intoType
+func (c *DictionaryConfig) MergeEntity(from, into string, intoType model.EntityType) error {
+	if strings.EqualFold(from, into) {
+		return fmt.Errorf("cannot merge %q into itself", from)
+	}
+	if c.Aliases == nil {
+		c.Aliases = make(map[string]string)
+	}
+	// Anything already merged into from now goes to into
+	for alias, canonical := range c.Aliases {
+		if strings.EqualFold(canonical, from) {
+			c.Aliases[alias] = into
+		}
+	}
+	c.Aliases[strings.ToLower(from)] = into
+	for name := range c.Types {
+		if strings.EqualFold(name, from) {
+			delete(c.Types, name)
+		}
+	}
+	c.Retype(into, intoType)
+	c.Unhide(into)
+	return nil
+}
+
+// Split undoes merges into name, returning the names restored as entities
+func (c *DictionaryConfig) Split(name string) []string {
+	var restored []string
+	for alias, canonical := range c.Aliases {
+		if strings.EqualFold(alias, name) || strings.EqualFold(canonical, name) {
+			restored = append(restored, alias)
+			delete(c.Aliases, alias)
+		}
+	}
+	return restored
+}
+
+// Retype assigns name to exactly one entity type
+func (c *DictionaryConfig) Retype(name string, t model.EntityType) {
+	if c.Types == nil {
+		c.Types = make(map[string]string)
+	}
+	for existing := range c.Types {
+		if strings.EqualFold(existing, name) {
+			delete(c.Types, existing)
+		}
+	}
+	c.Types[name] = strings.ToLower(t.String())
+}
+
+// Promote turns a term into a concept
+func (c *DictionaryConfig) Promote(name string) {
+	c.Retype(name, model.EntityConcept)
+}
+
+// Hide stops name from being reported as an entity
+func (c *DictionaryConfig) Hide(name string) {
+	for _, n := range c.Ignore {
+		if strings.EqualFold(n, name) {
+			return
+		}
+	}
+	c.Ignore = append(c.Ignore, name)
+}
+
+// Unhide reverses Hide
+func (c *DictionaryConfig) Unhide(name string) {
+	kept := c.Ignore[:0:0]
+	for _, n := range c.Ignore {
+		if !strings.EqualFold(n, name) {
+			kept = append(kept, n)
+		}
+	}
+	c.Ignore = kept
+}
+
+// LoadCuration reads a project's entity curation, empty if none exists
+func LoadCuration(beatsDir string) (DictionaryConfig, error) {
+	var cfg DictionaryConfig
+	path := filepath.Join(beatsDir, CurationFileName)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("reading entity curation: %w", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parsing entity curation: %w", err)
+	}
+	return cfg, nil
+}
+
+// SaveCuration writes a project's entity curation atomically
+func SaveCuration(beatsDir string, cfg DictionaryConfig) error {
+	path := filepath.Join(beatsDir, CurationFileName)
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling entity curation: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("writing temp entity curation: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("renaming entity curation: %w", err)
+	}
+	return nil
+}
@@ -1,6 +1,21 @@
 package entity
 
-import "github.com/bierlingm/beats_viewer/pkg/model"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bierlingm/beats_viewer/pkg/config"
+	"github.com/bierlingm/beats_viewer/pkg/model"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
 
 // Known people dictionary - common tech/startup figures
 var KnownPeople = []string{
@@ -26,10 +41,9 @@ var KnownConcepts = []string{
 	"synthesis", "pattern", "insight", "discovery",
 }
 
-// Known projects (customize for your context)
-var KnownProjects = []string{
-	"runcible", "modern-minuteman", "modern minuteman",
-}
+// Known projects. Personal projects belong in a dictionary config file
+// rather than here.
+var KnownProjects = []string{}
 
 // Known organizations
 var KnownOrganizations = []string{
@@ -45,3 +59,305 @@ var EntityDictionaries = map[model.EntityType][]string{
 	model.EntityProject:      KnownProjects,
 	model.EntityOrganization: KnownOrganizations,
 }
+
+// caseInsensitiveBuiltins are capitalized built-ins distinctive enough to
+// match in any case, e.g. "github" in a URL
+var caseInsensitiveBuiltins = []string{
+	"GitHub", "Supabase", "Ollama", "Cloudflare", "Vercel", "ChatGPT",
+	"VSCode", "TypeScript", "PostgreSQL", "Kubernetes", "WezTerm",
+	"Anthropic", "OpenAI", "Next.js", "DHH",
+}
+
+// extractionVersion changes whenever matching behaves differently, so
+// caches built by an older matcher are re-extracted
+const extractionVersion = 3
+
+// ConfigSection is the config file section holding entity dictionaries
+const ConfigSection = "entities"
+
+// DictionaryConfig is the user-editable form of an entity dictionary
+type DictionaryConfig struct {
+	People        []string          `json:"people,omitempty"`
+	Tools         []string          `json:"tools,omitempty"`
+	Concepts      []string          `json:"concepts,omitempty"`
+	Projects      []string          `json:"projects,omitempty"`
+	Organizations []string          `json:"organizations,omitempty"`
+	Aliases       map[string]string `json:"aliases,omitempty"` // alias -> canonical name, e.g. "gh" -> "GitHub"
+	Types         map[string]string `json:"types,omitempty"`   // name -> type, overriding the built-in type
+	Case          map[string]string `json:"case,omitempty"`    // name -> auto, exact or insensitive
+	Ignore        []string          `json:"ignore,omitempty"`  // names never reported as entities
+}
+
+// Dictionary is the merged set of known entities used for extraction
+type Dictionary struct {
+	Entries map[model.EntityType][]string `json:"entries"`
+	Aliases map[string]string             `json:"aliases"` // lowercased alias -> canonical name
+	Ignore  map[string]bool               `json:"ignore"`  // lowercased names
+	Case    map[string]CaseRule           `json:"case"`    // lowercased name -> case rule
+
+	matcher *Matcher
+}
+
+// BuiltinDictionary returns the dictionary compiled into btv
+func BuiltinDictionary() *Dictionary {
+	d := &Dictionary{
+		Entries: make(map[model.EntityType][]string),
+		Aliases: make(map[string]string),
+		Ignore:  make(map[string]bool),
+		Case:    make(map[string]CaseRule),
+	}
+	for entityType, names := range EntityDictionaries {
+		d.Entries[entityType] = append([]string(nil), names...)
+	}
+	for _, name := range caseInsensitiveBuiltins {
+		d.Case[strings.ToLower(name)] = CaseInsensitive
+	}
+	return d
+}
+
+// Merge adds a config's entries, aliases, type assignments and ignores on top
+// of the dictionary. Type assignments move a name to exactly one type.
+func (d *Dictionary) Merge(cfg DictionaryConfig) error {
+	d.matcher = nil
+
+	lists := map[model.EntityType][]string{
+		model.EntityPerson:       cfg.People,
+		model.EntityTool:         cfg.Tools,
+		model.EntityConcept:      cfg.Concepts,
+		model.EntityProject:      cfg.Projects,
+		model.EntityOrganization: cfg.Organizations,
+	}
+	for _, entityType := range model.AllEntityTypes() {
+		for _, name := range lists[entityType] {
+			d.add(entityType, name)
+		}
+	}
+
+	for name, typeName := range cfg.Types {
+		entityType, err := model.ParseEntityType(typeName)
+		if err != nil {
+			return fmt.Errorf("type for %q: %w", name, err)
+		}
+		d.remove(name)
+		d.add(entityType, name)
+	}
+
+	for alias, canonical := range cfg.Aliases {
+		if strings.TrimSpace(alias) == "" || strings.TrimSpace(canonical) == "" {
+			return fmt.Errorf("alias %q -> %q must not be empty", alias, canonical)
+		}
+		// An alias is another spelling, not an entity of its own
+		d.remove(alias)
+		d.Aliases[strings.ToLower(alias)] = canonical
+	}
+
+	for _, name := range cfg.Ignore {
+		d.Ignore[strings.ToLower(name)] = true
+	}
+
+	for name, rule := range cfg.Case {
+		switch CaseRule(rule) {
+		case CaseAuto, CaseExact, CaseInsensitive:
+			d.Case[strings.ToLower(name)] = CaseRule(rule)
+		default:
+			return fmt.Errorf("case for %q: unknown rule %q (want auto, exact or insensitive)", name, rule)
+		}
+	}
+
+	return nil
+}
+
+// Validate checks that every alias points at a known entity
+func (d *Dictionary) Validate() error {
+	for alias, canonical := range d.Aliases {
+		if _, ok := d.TypeOf(canonical); !ok {
+			return fmt.Errorf("alias %q points to %q, which is not in any entity list", alias, canonical)
+		}
+	}
+	return nil
+}
+
+// TypeOf returns the first type a name is listed under
+func (d *Dictionary) TypeOf(name string) (model.EntityType, bool) {
+	for _, entityType := range model.AllEntityTypes() {
+		for _, n := range d.Entries[entityType] {
+			if strings.EqualFold(n, name) {
+				return entityType, true
+			}
+		}
+	}
+	return model.EntityPerson, false
+}
+
+// Fingerprint identifies the dictionary's contents so extraction can be
+// redone when it changes
+func (d *Dictionary) Fingerprint() string {
+	entries := make(map[string][]string, len(d.Entries))
+	for entityType, names := range d.Entries {
+		sorted := append([]string(nil), names...)
+		sort.Strings(sorted)
+		entries[entityType.String()] = sorted
+	}
+	data, _ := json.Marshal(struct {
+		Version int
+		Entries map[string][]string
+		Aliases map[string]string
+		Ignore  map[string]bool
+		Case    map[string]CaseRule
+	}{extractionVersion, entries, d.Aliases, d.Ignore, d.Case})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// Matcher returns the dictionary's matcher, building it on first use
+func (d *Dictionary) Matcher() *Matcher {
+	if d.matcher == nil {
+		d.matcher = NewMatcher(d)
+	}
+	return d.matcher
+}
+
+func (d *Dictionary) caseRule(name string) CaseRule {
+	if rule, ok := d.Case[strings.ToLower(name)]; ok {
+		return rule
+	}
+	return CaseAuto
+}
+
+func (d *Dictionary) add(entityType model.EntityType, name string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return
+	}
+	for _, n := range d.Entries[entityType] {
+		if strings.EqualFold(n, name) {
+			return
+		}
+	}
+	d.Entries[entityType] = append(d.Entries[entityType], name)
+}
+
+func (d *Dictionary) remove(name string) {
+	for entityType, names := range d.Entries {
+		kept := names[:0:0]
+		for _, n := range names {
+			if !strings.EqualFold(n, name) {
+				kept = append(kept, n)
+			}
+		}
+		d.Entries[entityType] = kept
+	}
+}
+
+// Dictionary files hold a DictionaryConfig on its own, as JSON, YAML or TOML
+// by extension: DictionaryFile in the global config directory and
+// ProjectDictionaryFile in .beats/
+const (
+	DictionaryFile        = "dictionary"
+	ProjectDictionaryFile = "btv-dictionary"
+)
+
+var dictionaryExtensions = []string{".json", ".yaml", ".yml", ".toml"}
+
+// DictionaryPaths returns the dictionary files consulted for a project,
+// global first
+func DictionaryPaths(beatsDir string) []string {
+	var paths []string
+	if dir := config.GlobalDir(); dir != "" {
+		for _, ext := range dictionaryExtensions {
+			paths = append(paths, filepath.Join(dir, DictionaryFile+ext))
+		}
+	}
+	if beatsDir != "" {
+		for _, ext := range dictionaryExtensions {
+			paths = append(paths, filepath.Join(beatsDir, ProjectDictionaryFile+ext))
+		}
+	}
+	return paths
+}
+
+// dictionaryFiles returns the dictionary files that exist for a project
+func dictionaryFiles(beatsDir string) []string {
+	var paths []string
+	for _, path := range DictionaryPaths(beatsDir) {
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// decodeDictionaryFile reads a dictionary file in the format its extension
+// names
+func decodeDictionaryFile(path string) (DictionaryConfig, error) {
+	var cfg DictionaryConfig
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("reading %s: %w", path, err)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &cfg)
+	case ".toml":
+		err = toml.Unmarshal(data, &cfg)
+	default:
+		err = json.Unmarshal(data, &cfg)
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// LoadDictionary merges the global and project dictionary configs, then the
+// global and project dictionary files, then the project's entity curation,
+// over the built-ins. Unlike ripeness profiles, lists from all files are
+// combined rather than the project replacing the global ones. It returns the
+// files that contributed.
+func LoadDictionary(beatsDir string) (*Dictionary, []string, error) {
+	dict := BuiltinDictionary()
+
+	sources, err := config.EachSection(beatsDir, ConfigSection, func(path string, raw json.RawMessage) error {
+		var cfg DictionaryConfig
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			return fmt.Errorf("decoding %s in %s: %w", ConfigSection, path, err)
+		}
+		if err := dict.Merge(cfg); err != nil {
+			return fmt.Errorf("%s in %s: %w", ConfigSection, path, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return BuiltinDictionary(), sources, err
+	}
+
+	for _, path := range dictionaryFiles(beatsDir) {
+		cfg, err := decodeDictionaryFile(path)
+		if err != nil {
+			return BuiltinDictionary(), sources, err
+		}
+		if err := dict.Merge(cfg); err != nil {
+			return BuiltinDictionary(), sources, fmt.Errorf("%s: %w", path, err)
+		}
+		sources = append(sources, path)
+	}
+
+	if beatsDir != "" {
+		curation, err := LoadCuration(beatsDir)
+		if err != nil {
+			return BuiltinDictionary(), sources, err
+		}
+		if err := dict.Merge(curation); err != nil {
+			return BuiltinDictionary(), sources, fmt.Errorf("entity curation: %w", err)
+		}
+		if _, err := os.Stat(filepath.Join(beatsDir, CurationFileName)); err == nil {
+			sources = append(sources, filepath.Join(beatsDir, CurationFileName))
+		}
+	}
+
+	if err := dict.Validate(); err != nil {
+		return BuiltinDictionary(), sources, fmt.Errorf("invalid entity dictionary: %w", err)
+	}
+
+	return dict, sources, nil
+}
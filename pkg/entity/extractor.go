@@ -2,6 +2,7 @@ package entity
 
 import (
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/bierlingm/beats_viewer/pkg/model"
@@ -9,65 +10,168 @@ import (
 
 var capitalizedNamePattern = regexp.MustCompile(`\b([A-Z][a-z]+(?:\s+[A-Z][a-z]+)?)\b`)
 
-// Extract extracts entities from a single beat
+// Extractor finds entity mentions in beat content. The dictionary is the
+// default; an LLM extractor can add entities the dictionary does not know.
+type Extractor interface {
+	// Name identifies the extractor, e.g. "dictionary"
+	Name() string
+	// Mentions returns the entity mentions in content, ordered by position
+	Mentions(content string) []model.Mention
+	// Fingerprint changes whenever extraction would give different results,
+	// so cached entities are re-extracted
+	Fingerprint() string
+}
+
+// beatMentioner is implemented by extractors that track per-beat state, such
+// as which beats the LLM extractor has to retry
+type beatMentioner interface {
+	BeatMentions(beat model.Beat) []model.Mention
+}
+
+// Extract extracts entities from a single beat using the built-in dictionary
 func Extract(beat model.Beat) []model.Entity {
-	var entities []model.Entity
-	content := beat.Content
-	contentLower := strings.ToLower(content)
+	return BuiltinDictionary().Extract(beat)
+}
+
+// ExtractAll extracts entities from all beats with the built-in dictionary
+// and builds an index
+func ExtractAll(beats []model.Beat) ([]model.Entity, map[string][]string) {
+	return BuiltinDictionary().ExtractAll(beats)
+}
 
+// Extract extracts entities from a single beat
+func (d *Dictionary) Extract(beat model.Beat) []model.Entity {
+	return entitiesFromMentions(beat.ID, d.Mentions(beat.Content))
+}
+
+// entitiesFromMentions collapses a beat's mentions to one entity per name and type
+func entitiesFromMentions(beatID string, mentions []model.Mention) []model.Entity {
+	var entities []model.Entity
 	seen := make(map[string]bool)
 
-	for entityType, dict := range EntityDictionaries {
-		for _, name := range dict {
-			if strings.Contains(contentLower, strings.ToLower(name)) {
-				key := strings.ToLower(name) + "-" + entityType.String()
-				if !seen[key] {
-					seen[key] = true
-					entities = append(entities, model.Entity{
-						Name:    name,
-						Type:    entityType,
-						BeatIDs: []string{beat.ID},
-					})
-				}
-			}
+	for _, mention := range mentions {
+		id := mention.EntityID()
+		if seen[id] {
+			continue
 		}
+		seen[id] = true
+		entities = append(entities, model.Entity{
+			ID:      id,
+			Name:    mention.Name,
+			Type:    mention.Type,
+			BeatIDs: []string{beatID},
+		})
 	}
 
-	matches := capitalizedNamePattern.FindAllString(content, -1)
-	for _, match := range matches {
-		if isCommonWord(match) {
+	return entities
+}
+
+// Name identifies the dictionary extractor
+func (d *Dictionary) Name() string {
+	return "dictionary"
+}
+
+// Mentions finds dictionary entities in content, then capitalized names that
+// look like people. Single capitalized words starting a sentence are skipped
+// since capitalization there says nothing; list such names in a dictionary.
+func (d *Dictionary) Mentions(content string) []model.Mention {
+	return d.addCapitalizedNames(content, d.Matcher().Mentions(content))
+}
+
+// addCapitalizedNames adds capitalized names not overlapping mentions as people
+func (d *Dictionary) addCapitalizedNames(content string, mentions []model.Mention) []model.Mention {
+	known := make(map[string]bool)
+	for _, m := range mentions {
+		known[strings.ToLower(m.Name)] = true
+	}
+
+	for _, loc := range capitalizedNamePattern.FindAllStringIndex(content, -1) {
+		start, end := loc[0], loc[1]
+		match := content[start:end]
+		lower := strings.ToLower(match)
+		if isCommonWord(match) || known[lower] || d.Ignore[lower] || overlapsMention(mentions, start, end) {
 			continue
 		}
-		key := strings.ToLower(match) + "-person"
-		if !seen[key] {
-			seen[key] = true
-			entities = append(entities, model.Entity{
-				Name:    match,
-				Type:    model.EntityPerson,
-				BeatIDs: []string{beat.ID},
-			})
+		if !strings.ContainsAny(match, " \t\n") && atSentenceStart(content, start) {
+			continue
 		}
+		mentions = append(mentions, model.Mention{
+			Name:    match,
+			Type:    model.EntityPerson,
+			Start:   start,
+			End:     end,
+			Surface: match,
+		})
 	}
 
-	return entities
+	sort.Slice(mentions, func(i, j int) bool { return mentions[i].Start < mentions[j].Start })
+	return mentions
+}
+
+func overlapsMention(mentions []model.Mention, start, end int) bool {
+	for _, m := range mentions {
+		if start < m.End && m.Start < end {
+			return true
+		}
+	}
+	return false
+}
+
+// atSentenceStart reports whether only whitespace separates pos from the
+// start of content or the end of the previous sentence
+func atSentenceStart(content string, pos int) bool {
+	i := pos - 1
+	for i >= 0 && (content[i] == ' ' || content[i] == '\t') {
+		i--
+	}
+	if i < 0 {
+		return true
+	}
+	switch content[i] {
+	case '.', '!', '?', '\n', ':', '"', '(', '-', '*':
+		return true
+	}
+	return false
 }
 
 // ExtractAll extracts entities from all beats and builds an index
-func ExtractAll(beats []model.Beat) ([]model.Entity, map[string][]string) {
+func (d *Dictionary) ExtractAll(beats []model.Beat) ([]model.Entity, map[string][]string) {
+	entities, entityIndex, _ := d.ExtractAllWithMentions(beats)
+	return entities, entityIndex
+}
+
+// ExtractAllWithMentions extracts entities from all beats, builds an index
+// and keeps each beat's mention spans
+func (d *Dictionary) ExtractAllWithMentions(beats []model.Beat) ([]model.Entity, map[string][]string, map[string][]model.Mention) {
+	return ExtractAllWith(d, beats)
+}
+
+// ExtractAllWith extracts entities from all beats with x, builds an index
+// from entity ID to beat IDs and keeps each beat's mention spans
+func ExtractAllWith(x Extractor, beats []model.Beat) ([]model.Entity, map[string][]string, map[string][]model.Mention) {
 	entityMap := make(map[string]*model.Entity)
 	entityIndex := make(map[string][]string)
+	mentions := make(map[string][]model.Mention)
 
+	bm, perBeat := x.(beatMentioner)
 	for _, beat := range beats {
-		extracted := Extract(beat)
-		for _, e := range extracted {
-			key := strings.ToLower(e.Name) + "-" + e.Type.String()
-			if existing, ok := entityMap[key]; ok {
+		var beatMentions []model.Mention
+		if perBeat {
+			beatMentions = bm.BeatMentions(beat)
+		} else {
+			beatMentions = x.Mentions(beat.Content)
+		}
+		if len(beatMentions) > 0 {
+			mentions[beat.ID] = beatMentions
+		}
+		for _, e := range entitiesFromMentions(beat.ID, beatMentions) {
+			if existing, ok := entityMap[e.ID]; ok {
 				existing.BeatIDs = append(existing.BeatIDs, beat.ID)
 			} else {
 				entity := e
-				entityMap[key] = &entity
+				entityMap[e.ID] = &entity
 			}
-			entityIndex[e.Name] = append(entityIndex[e.Name], beat.ID)
+			entityIndex[e.ID] = append(entityIndex[e.ID], beat.ID)
 		}
 	}
 
@@ -76,7 +180,7 @@ func ExtractAll(beats []model.Beat) ([]model.Entity, map[string][]string) {
 		entities = append(entities, *e)
 	}
 
-	return entities, entityIndex
+	return entities, entityIndex, mentions
 }
 
 var commonWords = map[string]bool{
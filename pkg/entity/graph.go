@@ -0,0 +1,286 @@
+package entity
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/bierlingm/beats_viewer/pkg/model"
+)
+
+// GraphNode is an entity in the co-occurrence graph
+type GraphNode struct {
+	ID    string           `json:"id"` // see model.EntityID
+	Name  string           `json:"name"`
+	Type  model.EntityType `json:"type"`
+	Beats int              `json:"beats"`
+}
+
+// GraphEdge links two entities that appear in the same beats
+type GraphEdge struct {
+	SourceID string  `json:"source_id"`
+	TargetID string  `json:"target_id"`
+	Source   string  `json:"source"` // entity names
+	Target   string  `json:"target"`
+	Shared   int     `json:"shared"` // beats containing both
+	PMI      float64 `json:"pmi"`    // pointwise mutual information, in bits
+	NPMI     float64 `json:"npmi"`   // PMI normalized to [-1, 1]
+}
+
+// Weight returns the edge strength: normalized PMI when byPMI is set,
+// otherwise the number of shared beats
+func (e GraphEdge) Weight(byPMI bool) float64 {
+	if byPMI {
+		return e.NPMI
+	}
+	return float64(e.Shared)
+}
+
+// Graph records which entities appear together across beats
+type Graph struct {
+	Beats int         `json:"beats"` // beats with at least one entity
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+
+	adjacency map[string][]int // entity ID -> edge indexes
+	nodeIndex map[string]int   // entity ID -> node index
+	byName    map[string][]int // lowercased name -> node indexes, one per type
+}
+
+// BuildGraph builds the co-occurrence graph of entities sharing at least
+// minShared beats. Nodes are entity IDs, so a name found as two types is two
+// nodes.
+func BuildGraph(entities []model.Entity, minShared int) *Graph {
+	if minShared < 1 {
+		minShared = 1
+	}
+
+	type nodeInfo struct {
+		id    string
+		name  string
+		typ   model.EntityType
+		beats map[string]bool
+	}
+	nodes := make(map[string]*nodeInfo)
+	beatEntities := make(map[string][]string)
+
+	for _, e := range entities {
+		key := e.ID
+		n, ok := nodes[key]
+		if !ok {
+			n = &nodeInfo{id: key, name: e.Name, typ: e.Type, beats: make(map[string]bool)}
+			nodes[key] = n
+		}
+		for _, id := range e.BeatIDs {
+			if !n.beats[id] {
+				n.beats[id] = true
+				beatEntities[id] = append(beatEntities[id], key)
+			}
+		}
+	}
+
+	shared := make(map[[2]string]int)
+	for _, keys := range beatEntities {
+		sort.Strings(keys)
+		for i := 0; i < len(keys); i++ {
+			for j := i + 1; j < len(keys); j++ {
+				shared[[2]string{keys[i], keys[j]}]++
+			}
+		}
+	}
+
+	g := &Graph{Beats: len(beatEntities)}
+	total := float64(g.Beats)
+
+	for _, n := range nodes {
+		g.Nodes = append(g.Nodes, GraphNode{ID: n.id, Name: n.name, Type: n.typ, Beats: len(n.beats)})
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		if g.Nodes[i].Beats != g.Nodes[j].Beats {
+			return g.Nodes[i].Beats > g.Nodes[j].Beats
+		}
+		return g.Nodes[i].ID < g.Nodes[j].ID
+	})
+
+	for pair, count := range shared {
+		if count < minShared {
+			continue
+		}
+		a, b := nodes[pair[0]], nodes[pair[1]]
+		pA := float64(len(a.beats)) / total
+		pB := float64(len(b.beats)) / total
+		pAB := float64(count) / total
+
+		pmi := math.Log2(pAB / (pA * pB))
+		npmi := 1.0
+		if pAB < 1 {
+			npmi = pmi / -math.Log2(pAB)
+		}
+
+		g.Edges = append(g.Edges, GraphEdge{
+			SourceID: a.id,
+			TargetID: b.id,
+			Source:   a.name,
+			Target:   b.name,
+			Shared:   count,
+			PMI:      pmi,
+			NPMI:     npmi,
+		})
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].Shared != g.Edges[j].Shared {
+			return g.Edges[i].Shared > g.Edges[j].Shared
+		}
+		if g.Edges[i].SourceID != g.Edges[j].SourceID {
+			return g.Edges[i].SourceID < g.Edges[j].SourceID
+		}
+		return g.Edges[i].TargetID < g.Edges[j].TargetID
+	})
+
+	g.index()
+	return g
+}
+
+func (g *Graph) index() {
+	g.adjacency = make(map[string][]int)
+	g.nodeIndex = make(map[string]int, len(g.Nodes))
+	g.byName = make(map[string][]int)
+	for i, n := range g.Nodes {
+		g.nodeIndex[n.ID] = i
+		key := strings.ToLower(n.Name)
+		g.byName[key] = append(g.byName[key], i)
+	}
+	for i, e := range g.Edges {
+		g.adjacency[e.SourceID] = append(g.adjacency[e.SourceID], i)
+		g.adjacency[e.TargetID] = append(g.adjacency[e.TargetID], i)
+	}
+}
+
+// Node looks up an entity's node by ID
+func (g *Graph) Node(id string) (GraphNode, bool) {
+	i, ok := g.nodeIndex[id]
+	if !ok {
+		return GraphNode{}, false
+	}
+	return g.Nodes[i], true
+}
+
+// Resolve returns the IDs of the nodes key names: the node with that entity
+// ID, or every type's node when key is a plain name
+func (g *Graph) Resolve(key string) []string {
+	if _, _, err := model.ParseEntityID(key); err == nil {
+		if _, ok := g.nodeIndex[strings.ToLower(key)]; ok {
+			return []string{strings.ToLower(key)}
+		}
+		return nil
+	}
+	var ids []string
+	for _, i := range g.byName[strings.ToLower(key)] {
+		ids = append(ids, g.Nodes[i].ID)
+	}
+	return ids
+}
+
+// Related returns the strongest edges touching key, an entity ID or a name
+// covering every type, each oriented so that Target is the other entity
+func (g *Graph) Related(key string, limit int, byPMI bool) []GraphEdge {
+	ids := g.Resolve(key)
+	own := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		own[id] = true
+	}
+
+	var related []GraphEdge
+	for _, id := range ids {
+		for _, i := range g.adjacency[id] {
+			e := g.Edges[i]
+			if own[e.SourceID] && own[e.TargetID] {
+				continue
+			}
+			if own[e.TargetID] {
+				e.SourceID, e.TargetID = e.TargetID, e.SourceID
+				e.Source, e.Target = e.Target, e.Source
+			}
+			related = append(related, e)
+		}
+	}
+
+	sort.SliceStable(related, func(i, j int) bool {
+		wi, wj := related[i].Weight(byPMI), related[j].Weight(byPMI)
+		if wi != wj {
+			return wi > wj
+		}
+		return related[i].Shared > related[j].Shared
+	})
+
+	if limit > 0 && len(related) > limit {
+		related = related[:limit]
+	}
+	return related
+}
+
+// Neighborhood returns the subgraph of key, an entity ID or a name, and the
+// entities related to it
+func (g *Graph) Neighborhood(key string) *Graph {
+	keep := make(map[string]bool)
+	for _, id := range g.Resolve(key) {
+		keep[id] = true
+	}
+	for _, e := range g.Related(key, 0, false) {
+		keep[e.TargetID] = true
+	}
+
+	sub := &Graph{Beats: g.Beats}
+	for _, n := range g.Nodes {
+		if keep[n.ID] {
+			sub.Nodes = append(sub.Nodes, n)
+		}
+	}
+	for _, e := range g.Edges {
+		if keep[e.SourceID] && keep[e.TargetID] {
+			sub.Edges = append(sub.Edges, e)
+		}
+	}
+	sub.index()
+	return sub
+}
+
+var dotShapes = map[model.EntityType]string{
+	model.EntityPerson:       "ellipse",
+	model.EntityTool:         "box",
+	model.EntityConcept:      "diamond",
+	model.EntityProject:      "hexagon",
+	model.EntityOrganization: "house",
+}
+
+// DOT renders the graph in Graphviz format, with edge width following weight
+func (g *Graph) DOT(byPMI bool) string {
+	var sb strings.Builder
+	sb.WriteString("graph entities {\n")
+	sb.WriteString("  node [fontname=\"Helvetica\"];\n")
+
+	for _, n := range g.Nodes {
+		sb.WriteString(fmt.Sprintf("  %q [label=%q, shape=%s];\n",
+			n.ID, fmt.Sprintf("%s (%d)", n.Name, n.Beats), dotShapes[n.Type]))
+	}
+
+	maxWeight := 0.0
+	for _, e := range g.Edges {
+		maxWeight = math.Max(maxWeight, e.Weight(byPMI))
+	}
+	for _, e := range g.Edges {
+		width := 1.0
+		if maxWeight > 0 && e.Weight(byPMI) > 0 {
+			width = 1 + 4*e.Weight(byPMI)/maxWeight
+		}
+		label := fmt.Sprintf("%d", e.Shared)
+		if byPMI {
+			label = fmt.Sprintf("%.2f", e.NPMI)
+		}
+		sb.WriteString(fmt.Sprintf("  %q -- %q [label=%q, penwidth=%.1f];\n", e.SourceID, e.TargetID, label, width))
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
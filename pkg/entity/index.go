@@ -2,7 +2,6 @@ package entity
 
 import (
 	"sort"
-	"strings"
 
 	"github.com/bierlingm/beats_viewer/pkg/model"
 )
@@ -10,17 +9,16 @@ import (
 // Index provides fast entity lookups
 type Index struct {
 	entities    []model.Entity
-	byName      map[string]*model.Entity
 	byType      map[model.EntityType][]*model.Entity
 	byBeatID    map[string][]*model.Entity
 	entityIndex map[string][]string
 }
 
-// NewIndex creates a new entity index from entities
+// NewIndex creates a new entity index from entities and the entity ID to
+// beat IDs index
 func NewIndex(entities []model.Entity, entityIndex map[string][]string) *Index {
 	idx := &Index{
 		entities:    entities,
-		byName:      make(map[string]*model.Entity),
 		byType:      make(map[model.EntityType][]*model.Entity),
 		byBeatID:    make(map[string][]*model.Entity),
 		entityIndex: entityIndex,
@@ -28,8 +26,6 @@ func NewIndex(entities []model.Entity, entityIndex map[string][]string) *Index {
 
 	for i := range entities {
 		e := &entities[i]
-		key := strings.ToLower(e.Name)
-		idx.byName[key] = e
 		idx.byType[e.Type] = append(idx.byType[e.Type], e)
 		for _, beatID := range e.BeatIDs {
 			idx.byBeatID[beatID] = append(idx.byBeatID[beatID], e)
@@ -39,11 +35,6 @@ func NewIndex(entities []model.Entity, entityIndex map[string][]string) *Index {
 	return idx
 }
 
-// GetByName returns an entity by name (case-insensitive)
-func (idx *Index) GetByName(name string) *model.Entity {
-	return idx.byName[strings.ToLower(name)]
-}
-
 // GetByType returns all entities of a given type
 func (idx *Index) GetByType(entityType model.EntityType) []*model.Entity {
 	return idx.byType[entityType]
@@ -54,9 +45,9 @@ func (idx *Index) GetForBeat(beatID string) []*model.Entity {
 	return idx.byBeatID[beatID]
 }
 
-// GetBeatIDsForEntity returns beat IDs containing an entity
-func (idx *Index) GetBeatIDsForEntity(name string) []string {
-	return idx.entityIndex[name]
+// GetBeatIDsForEntity returns beat IDs containing an entity, by ID
+func (idx *Index) GetBeatIDsForEntity(id string) []string {
+	return idx.entityIndex[id]
 }
 
 // AllEntities returns all entities
@@ -0,0 +1,516 @@
+package entity
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats_viewer/pkg/config"
+	"github.com/bierlingm/beats_viewer/pkg/model"
+)
+
+// LLMConfigSection is the config file section configuring the LLM extractor
+const LLMConfigSection = "entity_llm"
+
+// LLMCacheFileName stores LLM extraction results alongside beats.jsonl
+const LLMCacheFileName = "btv-llm-entities.json"
+
+const (
+	LLMAPIOllama = "ollama" // Ollama's /api/chat
+	LLMAPIOpenAI = "openai" // any OpenAI-compatible /v1/chat/completions server
+
+	DefaultLLMURL   = "http://localhost:11434"
+	DefaultLLMModel = "llama3.2"
+)
+
+// llmPromptVersion changes whenever the prompt does, so cached results from
+// an older prompt are not reused
+const llmPromptVersion = 1
+
+// maxLLMFailures is how many requests may fail in a row before the endpoint
+// is treated as unavailable for the rest of the run
+const maxLLMFailures = 3
+
+// llmRetryInterval is how long failed beats wait before a load retries them,
+// so an endpoint that is down does not stall every load on its probe
+const llmRetryInterval = 10 * time.Minute
+
+const llmPrompt = `You extract named entities from short personal notes.
+Reply with JSON only, in the form {"entities": [{"name": "...", "type": "..."}]}.
+type is one of person, tool, concept, project, organization.
+Write each name exactly as it appears in the note. Include lowercase concepts
+that are central to the note, but not generic words.`
+
+// LLMConfig configures entity extraction with a local language model
+type LLMConfig struct {
+	Enabled        bool   `json:"enabled"`
+	API            string `json:"api,omitempty"`             // "ollama" (default) or "openai"
+	URL            string `json:"url,omitempty"`             // server base URL
+	Model          string `json:"model,omitempty"`           // model name
+	APIKeyEnv      string `json:"api_key_env,omitempty"`     // environment variable holding a bearer token
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty"` // per beat
+}
+
+// DefaultLLMConfig returns the LLM extractor settings used when config files
+// leave them out. The extractor is off unless enabled.
+func DefaultLLMConfig() LLMConfig {
+	return LLMConfig{
+		API:            LLMAPIOllama,
+		URL:            DefaultLLMURL,
+		Model:          DefaultLLMModel,
+		TimeoutSeconds: 60,
+	}
+}
+
+// LoadLLMConfig overlays the global and project LLM extractor settings on the
+// defaults and returns the files that contributed
+func LoadLLMConfig(beatsDir string) (LLMConfig, []string, error) {
+	cfg := DefaultLLMConfig()
+	sources, err := config.LoadSection(beatsDir, LLMConfigSection, &cfg)
+	if err != nil {
+		return DefaultLLMConfig(), sources, err
+	}
+	switch cfg.API {
+	case LLMAPIOllama, LLMAPIOpenAI:
+	default:
+		return DefaultLLMConfig(), sources, fmt.Errorf("%s: unknown api %q (want ollama or openai)", LLMConfigSection, cfg.API)
+	}
+	if cfg.TimeoutSeconds <= 0 {
+		cfg.TimeoutSeconds = DefaultLLMConfig().TimeoutSeconds
+	}
+	return cfg, sources, nil
+}
+
+// LLMEntity is an entity as returned by the model
+type LLMEntity struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// LLMCache keeps the entities the model returned, keyed by a hash of model,
+// prompt and content so each beat is only sent once, and the beats whose
+// request failed so a later load retries just those
+type LLMCache struct {
+	Results map[string][]LLMEntity `json:"results"`
+	Failed  map[string]bool        `json:"failed,omitempty"` // by beat ID
+	Tried   time.Time              `json:"tried,omitempty"`  // when a request last failed
+}
+
+// NewLLMCache returns an empty LLM cache
+func NewLLMCache() *LLMCache {
+	return &LLMCache{
+		Results: make(map[string][]LLMEntity),
+		Failed:  make(map[string]bool),
+	}
+}
+
+// LoadLLMCache reads cached LLM results, empty if none exist. Caches written
+// before failures were tracked hold the results map alone.
+func LoadLLMCache(beatsDir string) (*LLMCache, error) {
+	cache := NewLLMCache()
+	data, err := os.ReadFile(filepath.Join(beatsDir, LLMCacheFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cache, nil
+		}
+		return cache, fmt.Errorf("reading LLM entity cache: %w", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return NewLLMCache(), fmt.Errorf("parsing LLM entity cache: %w", err)
+	}
+	target := interface{}(cache)
+	if _, ok := fields["results"]; !ok {
+		target = &cache.Results
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return NewLLMCache(), fmt.Errorf("parsing LLM entity cache: %w", err)
+	}
+	if cache.Results == nil {
+		cache.Results = make(map[string][]LLMEntity)
+	}
+	if cache.Failed == nil {
+		cache.Failed = make(map[string]bool)
+	}
+	return cache, nil
+}
+
+// SaveLLMCache writes cached LLM results atomically
+func SaveLLMCache(beatsDir string, cache *LLMCache) error {
+	path := filepath.Join(beatsDir, LLMCacheFileName)
+
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return fmt.Errorf("marshaling LLM entity cache: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("writing temp LLM entity cache: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("renaming LLM entity cache: %w", err)
+	}
+	return nil
+}
+
+// LLMExtractor adds entities found by a language model to dictionary hits.
+// Dictionary matches win where they overlap, and the capitalized-name
+// heuristic only fills in what neither found. When the endpoint is down,
+// cached results are still used and other beats get dictionary hits only.
+// The endpoint is probed on the first request, so loads that find every beat
+// in the cache never wait on it.
+type LLMExtractor struct {
+	dict      *Dictionary
+	cfg       LLMConfig
+	client    *http.Client
+	cache     *LLMCache
+	beatsDir  string
+	probed    bool
+	available bool
+	inARow    int
+	dirty     bool
+}
+
+// NewLLMExtractor creates an LLM extractor
+func NewLLMExtractor(dict *Dictionary, cfg LLMConfig, cache *LLMCache, beatsDir string) *LLMExtractor {
+	if cache == nil {
+		cache = NewLLMCache()
+	}
+	x := &LLMExtractor{
+		dict:     dict,
+		cfg:      cfg,
+		client:   &http.Client{Timeout: time.Duration(cfg.TimeoutSeconds) * time.Second},
+		cache:    cache,
+		beatsDir: beatsDir,
+	}
+	return x
+}
+
+// LoadExtractor returns the project's extractor: the dictionary, or the LLM
+// extractor when it is enabled in config
+func LoadExtractor(beatsDir string) (Extractor, error) {
+	dict, _, err := LoadDictionary(beatsDir)
+	if err != nil {
+		return nil, err
+	}
+	cfg, _, err := LoadLLMConfig(beatsDir)
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.Enabled {
+		return dict, nil
+	}
+	// The cache only saves requests, so an unreadable one is started afresh
+	cache, _ := LoadLLMCache(beatsDir)
+	return NewLLMExtractor(dict, cfg, cache, beatsDir), nil
+}
+
+// Available reports whether the endpoint is being used, probing it if no
+// request has been sent yet
+func (x *LLMExtractor) Available() bool {
+	return x.online()
+}
+
+// online probes the endpoint once, on first use, and reports whether requests
+// are still being sent
+func (x *LLMExtractor) online() bool {
+	if !x.probed {
+		x.probed = true
+		x.available = x.checkAvailability()
+	}
+	return x.available
+}
+
+// Config returns the extractor's settings
+func (x *LLMExtractor) Config() LLMConfig {
+	return x.cfg
+}
+
+// Name identifies the extractor and its model
+func (x *LLMExtractor) Name() string {
+	if !x.online() {
+		return "dictionary (llm unavailable)"
+	}
+	return "llm:" + x.cfg.Model
+}
+
+// Fingerprint covers the dictionary, model and prompt. Beats the model could
+// not be asked about are tracked in the LLM cache instead; see Pending.
+func (x *LLMExtractor) Fingerprint() string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%d", x.dict.Fingerprint(), x.cfg.Model, llmPromptVersion)))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// Pending reports whether beats whose request failed are due a retry, so
+// entities should be extracted again. Only those beats are sent; the rest
+// come from the cache.
+func (x *LLMExtractor) Pending() bool {
+	return len(x.cache.Failed) > 0 && time.Since(x.cache.Tried) >= llmRetryInterval
+}
+
+// Mentions merges dictionary, model and capitalized-name mentions
+func (x *LLMExtractor) Mentions(content string) []model.Mention {
+	mentions, _ := x.mentions(content)
+	return mentions
+}
+
+// BeatMentions finds a beat's mentions like Mentions and records whether the
+// model could be asked about it
+func (x *LLMExtractor) BeatMentions(beat model.Beat) []model.Mention {
+	mentions, ok := x.mentions(beat.Content)
+	if !ok {
+		x.cache.Tried = time.Now()
+		x.dirty = true
+	}
+	if ok == x.cache.Failed[beat.ID] {
+		if ok {
+			delete(x.cache.Failed, beat.ID)
+		} else {
+			x.cache.Failed[beat.ID] = true
+		}
+		x.dirty = true
+	}
+	return mentions
+}
+
+// ForgetMissing drops failures recorded for beats no longer among beats
+func (x *LLMExtractor) ForgetMissing(beats []model.Beat) {
+	present := make(map[string]bool, len(beats))
+	for _, b := range beats {
+		present[b.ID] = true
+	}
+	for id := range x.cache.Failed {
+		if !present[id] {
+			delete(x.cache.Failed, id)
+			x.dirty = true
+		}
+	}
+}
+
+// mentions merges dictionary, model and capitalized-name mentions and reports
+// whether the model's answer was known
+func (x *LLMExtractor) mentions(content string) ([]model.Mention, bool) {
+	found, ok := x.llmMentions(content)
+	mentions := x.dict.Matcher().Mentions(content)
+	for _, m := range found {
+		if !overlapsMention(mentions, m.Start, m.End) {
+			mentions = append(mentions, m)
+		}
+	}
+	return x.dict.addCapitalizedNames(content, mentions), ok
+}
+
+// SaveCache writes any new results to the project's LLM cache
+func (x *LLMExtractor) SaveCache() error {
+	if !x.dirty || x.beatsDir == "" {
+		return nil
+	}
+	if err := SaveLLMCache(x.beatsDir, x.cache); err != nil {
+		return err
+	}
+	x.dirty = false
+	return nil
+}
+
+// llmMentions locates the model's entities in content. Names the dictionary
+// already knows are left to it, aliases resolve to their canonical name, and
+// names that do not occur in the content are dropped. It reports false when
+// the model could not be asked.
+func (x *LLMExtractor) llmMentions(content string) ([]model.Mention, bool) {
+	entities, ok := x.entitiesFor(content)
+	if len(entities) == 0 {
+		return nil, ok
+	}
+
+	found := &Dictionary{
+		Entries: make(map[model.EntityType][]string),
+		Aliases: make(map[string]string),
+		Ignore:  x.dict.Ignore,
+		Case:    make(map[string]CaseRule),
+	}
+	for _, e := range entities {
+		name := strings.TrimSpace(e.Name)
+		if name == "" || x.dict.Ignore[strings.ToLower(name)] {
+			continue
+		}
+		if _, known := x.dict.TypeOf(name); known {
+			continue
+		}
+		if _, alias := x.dict.Aliases[strings.ToLower(name)]; alias {
+			continue
+		}
+		entityType, err := model.ParseEntityType(e.Type)
+		if err != nil {
+			continue
+		}
+		found.add(entityType, name)
+	}
+	return NewMatcher(found).Mentions(content), true
+}
+
+func (x *LLMExtractor) cacheKey(content string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d\x00%s", x.cfg.Model, llmPromptVersion, content)))
+	return hex.EncodeToString(sum[:])
+}
+
+// entitiesFor returns the model's entities for content, from the cache if
+// possible. It reports false when the endpoint is down or the request failed.
+func (x *LLMExtractor) entitiesFor(content string) ([]LLMEntity, bool) {
+	if strings.TrimSpace(content) == "" {
+		return nil, true
+	}
+	key := x.cacheKey(content)
+	if entities, ok := x.cache.Results[key]; ok {
+		return entities, true
+	}
+	if !x.online() {
+		return nil, false
+	}
+
+	entities, err := x.request(content)
+	if err != nil {
+		x.inARow++
+		if x.inARow >= maxLLMFailures {
+			x.available = false
+		}
+		return nil, false
+	}
+	x.inARow = 0
+	x.cache.Results[key] = entities
+	x.dirty = true
+	return entities, true
+}
+
+type llmMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// request asks the model for content's entities. A reply that is not the
+// expected JSON counts as no entities rather than an error, since asking
+// again would give the same answer.
+func (x *LLMExtractor) request(content string) ([]LLMEntity, error) {
+	messages := []llmMessage{
+		{Role: "system", Content: llmPrompt},
+		{Role: "user", Content: content},
+	}
+
+	var body interface{}
+	var endpoint string
+	switch x.cfg.API {
+	case LLMAPIOpenAI:
+		endpoint = openAIEndpoint(x.cfg.URL, "chat/completions")
+		body = map[string]interface{}{
+			"model":           x.cfg.Model,
+			"messages":        messages,
+			"temperature":     0,
+			"response_format": map[string]string{"type": "json_object"},
+		}
+	default:
+		endpoint = strings.TrimRight(x.cfg.URL, "/") + "/api/chat"
+		body = map[string]interface{}{
+			"model":    x.cfg.Model,
+			"messages": messages,
+			"stream":   false,
+			"format":   "json",
+			"options":  map[string]interface{}{"temperature": 0},
+		}
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+	req, err := http.NewRequest("POST", endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	x.authorize(req)
+
+	resp, err := x.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sending request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", x.cfg.API, resp.StatusCode)
+	}
+
+	var reply struct {
+		Message llmMessage `json:"message"` // ollama
+		Choices []struct {
+			Message llmMessage `json:"message"`
+		} `json:"choices"` // openai
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+	text := reply.Message.Content
+	if len(reply.Choices) > 0 {
+		text = reply.Choices[0].Message.Content
+	}
+
+	var parsed struct {
+		Entities []LLMEntity `json:"entities"`
+	}
+	if err := json.Unmarshal([]byte(text), &parsed); err != nil {
+		return []LLMEntity{}, nil
+	}
+	if parsed.Entities == nil {
+		parsed.Entities = []LLMEntity{}
+	}
+	return parsed.Entities, nil
+}
+
+func (x *LLMExtractor) authorize(req *http.Request) {
+	if x.cfg.APIKeyEnv == "" {
+		return
+	}
+	if key := os.Getenv(x.cfg.APIKeyEnv); key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+}
+
+func (x *LLMExtractor) checkAvailability() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	url := strings.TrimRight(x.cfg.URL, "/") + "/api/tags"
+	if x.cfg.API == LLMAPIOpenAI {
+		url = openAIEndpoint(x.cfg.URL, "models")
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return false
+	}
+	x.authorize(req)
+
+	resp, err := x.client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// openAIEndpoint joins path onto an OpenAI-compatible base URL, which may or
+// may not already end in /v1
+func openAIEndpoint(base, path string) string {
+	base = strings.TrimRight(base, "/")
+	if !strings.HasSuffix(base, "/v1") {
+		base += "/v1"
+	}
+	return base + "/" + path
+}
@@ -0,0 +1,373 @@
+package entity
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bierlingm/beats_viewer/pkg/model"
+)
+
+// CaseRule controls how a dictionary entry's case must match the content
+type CaseRule string
+
+const (
+	// CaseAuto matches lowercase entries in any case and capitalized entries
+	// only as written or in all caps, so "Go" does not match "go"
+	CaseAuto        CaseRule = "auto"
+	CaseExact       CaseRule = "exact"
+	CaseInsensitive CaseRule = "insensitive"
+)
+
+// contextWindow is how many tokens either side of a mention are checked for
+// disambiguation cues
+const contextWindow = 3
+
+// typeCues are words near a mention that suggest which type an ambiguous
+// name has in this beat
+var typeCues = map[model.EntityType][]string{
+	model.EntityPerson: {
+		"said", "says", "told", "asked", "thinks", "suggested", "mentioned",
+		"met", "meeting", "call", "called", "emailed", "wrote", "helped", "recommended",
+	},
+	model.EntityTool: {
+		"using", "use", "used", "uses", "install", "installed", "run", "running",
+		"via", "deploy", "deployed", "api", "cli", "config", "plugin", "integration",
+	},
+	model.EntityOrganization: {
+		"at", "joined", "company", "startup", "raised", "acquired", "hiring",
+		"hired", "works", "announced", "inc", "funding", "team",
+	},
+	model.EntityProject: {
+		"shipped", "ship", "release", "released", "repo", "milestone", "roadmap",
+		"launch", "launched",
+	},
+}
+
+// tiePrecedence breaks ties between types when context gives no cue
+var tiePrecedence = []model.EntityType{
+	model.EntityTool,
+	model.EntityOrganization,
+	model.EntityProject,
+	model.EntityPerson,
+	model.EntityConcept,
+}
+
+type token struct {
+	start, end int
+	lower      string
+}
+
+type pattern struct {
+	name   string // canonical name reported for matches
+	form   string // the entry as written, for case checks
+	tokens []string
+	seps   []string // normalized separators between tokens
+	types  []model.EntityType
+	rule   CaseRule
+	alias  bool
+}
+
+type acNode struct {
+	next map[string]int
+	fail int
+	out  []int // pattern indexes ending here
+}
+
+// Matcher finds dictionary entries in text as whole tokens using an
+// Aho-Corasick automaton over lowercased tokens
+type Matcher struct {
+	patterns []pattern
+	nodes    []acNode
+}
+
+// NewMatcher builds a matcher for a dictionary's entries and aliases.
+// Ignored names are left out.
+func NewMatcher(d *Dictionary) *Matcher {
+	m := &Matcher{nodes: []acNode{{next: make(map[string]int)}}}
+	byKey := make(map[string]int)
+
+	addPattern := func(form, name string, entityType model.EntityType, alias bool) {
+		if d.Ignore[strings.ToLower(name)] {
+			return
+		}
+		toks, seps := tokenizeName(form)
+		if len(toks) == 0 {
+			return
+		}
+		key := strings.Join(toks, "\x00") + "\x01" + strings.Join(seps, "\x00")
+		if idx, ok := byKey[key]; ok {
+			p := &m.patterns[idx]
+			if p.alias && !alias {
+				// A real entry wins over an alias spelled the same way
+				*p = pattern{name: name, form: form, tokens: toks, seps: seps, rule: d.caseRule(form)}
+			}
+			for _, t := range p.types {
+				if t == entityType {
+					return
+				}
+			}
+			p.types = append(p.types, entityType)
+			return
+		}
+
+		rule := d.caseRule(form)
+		if alias {
+			rule = CaseInsensitive
+		}
+		byKey[key] = len(m.patterns)
+		m.patterns = append(m.patterns, pattern{
+			name:   name,
+			form:   form,
+			tokens: toks,
+			seps:   seps,
+			types:  []model.EntityType{entityType},
+			rule:   rule,
+			alias:  alias,
+		})
+	}
+
+	for _, entityType := range model.AllEntityTypes() {
+		for _, name := range d.Entries[entityType] {
+			addPattern(name, name, entityType, false)
+		}
+	}
+
+	aliases := make([]string, 0, len(d.Aliases))
+	for alias := range d.Aliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		canonical := d.Aliases[alias]
+		for _, entityType := range model.AllEntityTypes() {
+			for _, name := range d.Entries[entityType] {
+				if strings.EqualFold(name, canonical) {
+					addPattern(alias, name, entityType, true)
+				}
+			}
+		}
+	}
+
+	for i, p := range m.patterns {
+		node := 0
+		for _, tok := range p.tokens {
+			next, ok := m.nodes[node].next[tok]
+			if !ok {
+				next = len(m.nodes)
+				m.nodes = append(m.nodes, acNode{next: make(map[string]int)})
+				m.nodes[node].next[tok] = next
+			}
+			node = next
+		}
+		m.nodes[node].out = append(m.nodes[node].out, i)
+	}
+
+	// Breadth-first failure links, as in the classic construction
+	queue := []int{}
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for tok, child := range m.nodes[node].next {
+			fail := m.nodes[node].fail
+			for fail != 0 {
+				if _, ok := m.nodes[fail].next[tok]; ok {
+					break
+				}
+				fail = m.nodes[fail].fail
+			}
+			if next, ok := m.nodes[fail].next[tok]; ok && next != child {
+				m.nodes[child].fail = next
+			}
+			m.nodes[child].out = append(m.nodes[child].out, m.nodes[m.nodes[child].fail].out...)
+			queue = append(queue, child)
+		}
+	}
+
+	return m
+}
+
+// Mentions returns the dictionary entries found in content. Overlapping
+// matches resolve to the leftmost, longest one, and names listed under
+// several types are disambiguated from nearby words.
+func (m *Matcher) Mentions(content string) []model.Mention {
+	toks := tokenize(content)
+
+	type candidate struct {
+		first, last int // token indexes
+		pattern     int
+	}
+	var candidates []candidate
+
+	node := 0
+	for i, tok := range toks {
+		for node != 0 {
+			if _, ok := m.nodes[node].next[tok.lower]; ok {
+				break
+			}
+			node = m.nodes[node].fail
+		}
+		if next, ok := m.nodes[node].next[tok.lower]; ok {
+			node = next
+		}
+		for _, pi := range m.nodes[node].out {
+			p := m.patterns[pi]
+			first := i - len(p.tokens) + 1
+			if m.accepts(p, content, toks[first:i+1]) {
+				candidates = append(candidates, candidate{first: first, last: i, pattern: pi})
+			}
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].first != candidates[j].first {
+			return candidates[i].first < candidates[j].first
+		}
+		return candidates[i].last > candidates[j].last
+	})
+
+	var mentions []model.Mention
+	covered := -1
+	for _, c := range candidates {
+		if c.first <= covered {
+			continue
+		}
+		covered = c.last
+		p := m.patterns[c.pattern]
+		start, end := toks[c.first].start, toks[c.last].end
+		mentions = append(mentions, model.Mention{
+			Name:    p.name,
+			Type:    disambiguate(p.types, toks, c.first, c.last),
+			Start:   start,
+			End:     end,
+			Surface: content[start:end],
+		})
+	}
+	return mentions
+}
+
+// accepts checks separators and case for a token-level match
+func (m *Matcher) accepts(p pattern, content string, toks []token) bool {
+	for i, sep := range p.seps {
+		if normalizeSep(content[toks[i].end:toks[i+1].start]) != sep {
+			return false
+		}
+	}
+
+	surface := content[toks[0].start:toks[len(toks)-1].end]
+	switch p.rule {
+	case CaseInsensitive:
+		return true
+	case CaseExact:
+		return surface == p.form
+	default:
+		if p.form == strings.ToLower(p.form) {
+			return true
+		}
+		return surface == p.form || surface == strings.ToUpper(p.form)
+	}
+}
+
+func disambiguate(types []model.EntityType, toks []token, first, last int) model.EntityType {
+	if len(types) == 1 {
+		return types[0]
+	}
+
+	lo := first - contextWindow
+	if lo < 0 {
+		lo = 0
+	}
+	hi := last + contextWindow
+	if hi >= len(toks) {
+		hi = len(toks) - 1
+	}
+
+	scores := make(map[model.EntityType]int)
+	for i := lo; i <= hi; i++ {
+		if i >= first && i <= last {
+			continue
+		}
+		for _, t := range types {
+			for _, cue := range typeCues[t] {
+				if toks[i].lower == cue {
+					scores[t]++
+				}
+			}
+		}
+	}
+
+	best := types[0]
+	bestScore := -1
+	for _, t := range tiePrecedence {
+		if !hasType(types, t) {
+			continue
+		}
+		if scores[t] > bestScore {
+			best = t
+			bestScore = scores[t]
+		}
+	}
+	return best
+}
+
+func hasType(types []model.EntityType, t model.EntityType) bool {
+	for _, candidate := range types {
+		if candidate == t {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenize splits text into runs of letters and digits
+func tokenize(s string) []token {
+	var toks []token
+	start := -1
+	for i, r := range s {
+		if isTokenRune(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			toks = append(toks, token{start: start, end: i, lower: strings.ToLower(s[start:i])})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		toks = append(toks, token{start: start, end: len(s), lower: strings.ToLower(s[start:])})
+	}
+	return toks
+}
+
+// tokenizeName splits a dictionary entry into lowercased tokens and the
+// normalized separators between them, e.g. "Next.js" into [next js] and ["."]
+func tokenizeName(name string) ([]string, []string) {
+	toks := tokenize(name)
+	words := make([]string, len(toks))
+	var seps []string
+	for i, tok := range toks {
+		words[i] = tok.lower
+		if i > 0 {
+			seps = append(seps, normalizeSep(name[toks[i-1].end:tok.start]))
+		}
+	}
+	return words, seps
+}
+
+// normalizeSep collapses whitespace so "Paul  Graham" matches "Paul Graham"
+func normalizeSep(sep string) string {
+	if strings.TrimSpace(sep) == "" {
+		return " "
+	}
+	return strings.Join(strings.Fields(sep), " ")
+}
+
+func isTokenRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
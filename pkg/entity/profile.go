@@ -0,0 +1,243 @@
+package entity
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bierlingm/beats_viewer/pkg/model"
+)
+
+// snippetContext is how many bytes of content either side of a mention are
+// kept in its snippet
+const snippetContext = 40
+
+// MentionContext is one mention of an entity with the text around it
+type MentionContext struct {
+	BeatID    string        `json:"beat_id"`
+	CreatedAt time.Time     `json:"created_at"`
+	Mention   model.Mention `json:"mention"`
+	Snippet   string        `json:"snippet"`
+	Start     int           `json:"start"` // byte offset of the mention in Snippet
+	End       int           `json:"end"`
+}
+
+// FacetCount is how many of an entity's beats fall under a channel, source,
+// chain or cluster
+type FacetCount struct {
+	ID    string `json:"id,omitempty"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// EntityProfile gathers everything the beats say about one entity
+type EntityProfile struct {
+	ID          string           `json:"id,omitempty"` // set when built for one type
+	Name        string           `json:"name"`
+	Type        model.EntityType `json:"type"`
+	BeatIDs     []string         `json:"beat_ids"`
+	FirstSeen   time.Time        `json:"first_seen"`
+	LastSeen    time.Time        `json:"last_seen"`
+	Surfaces    []string         `json:"surfaces,omitempty"` // other spellings seen, e.g. aliases
+	Mentions    []MentionContext `json:"mentions"`
+	Channels    []FacetCount     `json:"channels"`
+	Sources     []FacetCount     `json:"sources"`
+	Related     []GraphEdge      `json:"related"`
+	LinkedBeads []string         `json:"linked_beads"`
+	Chains      []FacetCount     `json:"chains"`
+	Clusters    []FacetCount     `json:"clusters"`
+}
+
+// BuildProfile builds the profile of an entity given by ID, or of every
+// type sharing a name when given a plain name. It returns false if no beat
+// mentions it. Mentions are newest first.
+func BuildProfile(key string, beats []model.EnrichedBeat, entities []model.Entity, graph *Graph, relatedLimit int) (*EntityProfile, bool) {
+	p := &EntityProfile{Name: key, Surfaces: []string{}, LinkedBeads: []string{}}
+	matches := func(name string, t model.EntityType) bool {
+		return strings.EqualFold(name, key)
+	}
+	if _, _, err := model.ParseEntityID(key); err == nil {
+		p.ID = strings.ToLower(key)
+		matches = func(name string, t model.EntityType) bool {
+			return model.EntityID(t, name) == p.ID
+		}
+	}
+
+	ids := make(map[string]bool)
+	typeN := -1
+	for _, e := range entities {
+		if !matches(e.Name, e.Type) {
+			continue
+		}
+		p.Name = e.Name
+		if len(e.BeatIDs) > typeN {
+			p.Type = e.Type
+			typeN = len(e.BeatIDs)
+		}
+		for _, id := range e.BeatIDs {
+			ids[id] = true
+		}
+	}
+	if len(ids) == 0 {
+		return nil, false
+	}
+
+	channels := make(map[string]int)
+	sources := make(map[string]int)
+	chains := make(map[string]int)
+	clusters := make(map[string]int)
+	beads := make(map[string]bool)
+	surfaces := make(map[string]bool)
+
+	for _, eb := range beats {
+		if !ids[eb.ID] {
+			continue
+		}
+		p.BeatIDs = append(p.BeatIDs, eb.ID)
+		if p.FirstSeen.IsZero() || eb.CreatedAt.Before(p.FirstSeen) {
+			p.FirstSeen = eb.CreatedAt
+		}
+		if eb.CreatedAt.After(p.LastSeen) {
+			p.LastSeen = eb.CreatedAt
+		}
+
+		channels[eb.Taxonomy.Channel.String()]++
+		sources[eb.Taxonomy.Source.String()]++
+		for _, id := range eb.ChainIDs {
+			chains[id]++
+		}
+		if eb.ClusterID != "" {
+			clusters[eb.ClusterID]++
+		}
+		for _, bead := range eb.LinkedBeads {
+			if !beads[bead] {
+				beads[bead] = true
+				p.LinkedBeads = append(p.LinkedBeads, bead)
+			}
+		}
+
+		for _, m := range eb.Mentions {
+			if !matches(m.Name, m.Type) {
+				continue
+			}
+			if !strings.EqualFold(m.Surface, p.Name) {
+				surfaces[m.Surface] = true
+			}
+			snippet, start, end := Snippet(eb.Content, m.Start, m.End)
+			p.Mentions = append(p.Mentions, MentionContext{
+				BeatID:    eb.ID,
+				CreatedAt: eb.CreatedAt,
+				Mention:   m,
+				Snippet:   snippet,
+				Start:     start,
+				End:       end,
+			})
+		}
+	}
+
+	sort.SliceStable(p.Mentions, func(i, j int) bool {
+		return p.Mentions[i].CreatedAt.After(p.Mentions[j].CreatedAt)
+	})
+	for s := range surfaces {
+		p.Surfaces = append(p.Surfaces, s)
+	}
+	sort.Strings(p.Surfaces)
+	sort.Strings(p.LinkedBeads)
+
+	p.Channels = sortedCounts(channels)
+	p.Sources = sortedCounts(sources)
+	p.Chains = sortedCounts(chains)
+	p.Clusters = sortedCounts(clusters)
+	if graph != nil {
+		p.Related = graph.Related(key, relatedLimit, false)
+	}
+	return p, true
+}
+
+// NameChains replaces chain IDs in the profile with chain names
+func (p *EntityProfile) NameChains(chains []model.Chain) {
+	names := make(map[string]string, len(chains))
+	for _, c := range chains {
+		names[c.ID] = c.Name
+	}
+	nameFacets(p.Chains, names)
+}
+
+// NameClusters replaces cluster IDs in the profile with cluster names
+func (p *EntityProfile) NameClusters(clusters []model.Cluster) {
+	names := make(map[string]string, len(clusters))
+	for _, c := range clusters {
+		names[c.ID] = c.Name
+	}
+	nameFacets(p.Clusters, names)
+}
+
+func nameFacets(facets []FacetCount, names map[string]string) {
+	for i := range facets {
+		facets[i].ID = facets[i].Name
+		if name, ok := names[facets[i].ID]; ok && name != "" {
+			facets[i].Name = name
+		}
+	}
+}
+
+func sortedCounts(counts map[string]int) []FacetCount {
+	facets := make([]FacetCount, 0, len(counts))
+	for name, n := range counts {
+		facets = append(facets, FacetCount{Name: name, Count: n})
+	}
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Name < facets[j].Name
+	})
+	return facets
+}
+
+// Snippet cuts the text around content[start:end] on one line, returning it
+// with the mention's offsets inside the snippet
+func Snippet(content string, start, end int) (string, int, int) {
+	if start < 0 || end > len(content) || start > end {
+		return "", 0, 0
+	}
+
+	from := start - snippetContext
+	if from < 0 {
+		from = 0
+	}
+	to := end + snippetContext
+	if to > len(content) {
+		to = len(content)
+	}
+	for from > 0 && !utf8.RuneStart(content[from]) {
+		from--
+	}
+	for to < len(content) && !utf8.RuneStart(content[to]) {
+		to++
+	}
+	// Start and end on word boundaries where the cut lands mid-word
+	if from > 0 {
+		if i := strings.IndexAny(content[from:start], " \t\n"); i >= 0 {
+			from += i + 1
+		}
+	}
+	if to < len(content) {
+		if i := strings.LastIndexAny(content[end:to], " \t\n"); i >= 0 {
+			to = end + i
+		}
+	}
+
+	prefix, suffix := "", ""
+	if from > 0 {
+		prefix = "…"
+	}
+	if to < len(content) {
+		suffix = "…"
+	}
+	// Newlines become spaces, which keeps byte offsets unchanged
+	text := strings.NewReplacer("\n", " ", "\t", " ", "\r", " ").Replace(content[from:to])
+	snippet := prefix + text + suffix
+	return snippet, len(prefix) + start - from, len(prefix) + end - from
+}
@@ -0,0 +1,182 @@
+package entity
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats_viewer/pkg/model"
+	"github.com/bierlingm/beats_viewer/pkg/timeline"
+)
+
+// Trend classifies how attention to an entity is changing
+type Trend string
+
+const (
+	TrendRising Trend = "rising"
+	TrendFading Trend = "fading"
+	TrendSteady Trend = "steady"
+	TrendNew    Trend = "new" // first mentioned in the recent window
+)
+
+// trendThreshold is the relative change needed to count as rising or fading
+const trendThreshold = 0.5
+
+// TrendPoint is an entity's beat count in one timeline bucket
+type TrendPoint struct {
+	Date  time.Time `json:"date"`
+	Count int       `json:"count"`
+}
+
+// EntityTrend is an entity's time series and how it compares across the
+// recent and previous windows
+type EntityTrend struct {
+	Name     string           `json:"name"`
+	Type     model.EntityType `json:"type"`
+	Series   []TrendPoint     `json:"series"`
+	Recent   int              `json:"recent"`   // beats in the recent window
+	Previous int              `json:"previous"` // beats in the window before it
+	Change   float64          `json:"change"`   // (recent - previous) / max(previous, 1)
+	Trend    Trend            `json:"trend"`
+}
+
+// TrendWindow is the span compared: the last Periods periods at the zoom
+// level, ending with the period of the latest beat, against the same number
+// of periods before them
+type TrendWindow struct {
+	Zoom          timeline.ZoomLevel
+	Periods       int
+	RecentStart   time.Time
+	PreviousStart time.Time
+}
+
+// BuildTrends builds per-entity series from the timeline's buckets and
+// classifies each entity active in either window. Entities not mentioned in
+// either window are left out.
+func BuildTrends(entities []model.Entity, data *timeline.TimelineData, periods int) ([]EntityTrend, TrendWindow) {
+	if periods < 1 {
+		periods = 1
+	}
+	window := TrendWindow{Zoom: data.ZoomLevel, Periods: periods}
+	if len(data.Buckets) == 0 {
+		return nil, window
+	}
+	latest := data.Buckets[len(data.Buckets)-1].Date
+	window.RecentStart = timeline.PeriodsBefore(latest, data.ZoomLevel, periods-1)
+	window.PreviousStart = timeline.PeriodsBefore(window.RecentStart, data.ZoomLevel, periods)
+
+	bucketOf := make(map[string]int)
+	for i, b := range data.Buckets {
+		for _, id := range b.BeatIDs {
+			bucketOf[id] = i
+		}
+	}
+
+	// An entity listed under several types is one series, as in the graph
+	type series struct {
+		name  string
+		typ   model.EntityType
+		typeN int
+		beats map[string]bool
+	}
+	byName := make(map[string]*series)
+	var order []string
+	for _, e := range entities {
+		key := strings.ToLower(e.Name)
+		s, ok := byName[key]
+		if !ok {
+			s = &series{name: e.Name, typ: e.Type, beats: make(map[string]bool)}
+			byName[key] = s
+			order = append(order, key)
+		}
+		if len(e.BeatIDs) > s.typeN {
+			s.typ = e.Type
+			s.typeN = len(e.BeatIDs)
+		}
+		for _, id := range e.BeatIDs {
+			s.beats[id] = true
+		}
+	}
+
+	var trends []EntityTrend
+	for _, key := range order {
+		s := byName[key]
+		counts := make([]int, len(data.Buckets))
+		for id := range s.beats {
+			if i, ok := bucketOf[id]; ok {
+				counts[i]++
+			}
+		}
+
+		t := EntityTrend{Name: s.name, Type: s.typ}
+		earlier := 0
+		for i, b := range data.Buckets {
+			t.Series = append(t.Series, TrendPoint{Date: b.Date, Count: counts[i]})
+			switch {
+			case !b.Date.Before(window.RecentStart):
+				t.Recent += counts[i]
+			case !b.Date.Before(window.PreviousStart):
+				t.Previous += counts[i]
+				earlier += counts[i]
+			default:
+				earlier += counts[i]
+			}
+		}
+		if t.Recent == 0 && t.Previous == 0 {
+			continue
+		}
+
+		base := t.Previous
+		if base < 1 {
+			base = 1
+		}
+		t.Change = float64(t.Recent-t.Previous) / float64(base)
+		t.Trend = classifyTrend(t, earlier)
+		trends = append(trends, t)
+	}
+
+	sort.SliceStable(trends, func(i, j int) bool {
+		di, dj := trends[i].Recent-trends[i].Previous, trends[j].Recent-trends[j].Previous
+		if di != dj {
+			return di > dj
+		}
+		return trends[i].Name < trends[j].Name
+	})
+	return trends, window
+}
+
+func classifyTrend(t EntityTrend, earlier int) Trend {
+	switch {
+	case earlier == 0 && t.Recent > 0:
+		return TrendNew
+	case t.Recent > t.Previous && t.Change >= trendThreshold:
+		return TrendRising
+	case t.Recent < t.Previous && t.Change <= -trendThreshold:
+		return TrendFading
+	}
+	return TrendSteady
+}
+
+// TopTrends splits trends into the strongest rising (new entities included)
+// and fading ones, at most limit each
+func TopTrends(trends []EntityTrend, limit int) (rising, fading []EntityTrend) {
+	for _, t := range trends {
+		switch t.Trend {
+		case TrendRising, TrendNew:
+			rising = append(rising, t)
+		case TrendFading:
+			fading = append(fading, t)
+		}
+	}
+	// trends are sorted by growth, so the strongest fades are at the end
+	for i, j := 0, len(fading)-1; i < j; i, j = i+1, j-1 {
+		fading[i], fading[j] = fading[j], fading[i]
+	}
+	if limit > 0 && len(rising) > limit {
+		rising = rising[:limit]
+	}
+	if limit > 0 && len(fading) > limit {
+		fading = fading[:limit]
+	}
+	return rising, fading
+}
@@ -0,0 +1,389 @@
+package loader
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bierlingm/beats_viewer/pkg/model"
+)
+
+// AppendBeadLink appends a link between a beat and a bead to the project's
+// bead link log
+func AppendBeadLink(beatsDir string, link model.BeadLink) error {
+	path := filepath.Join(beatsDir, model.BeadLinksFileName)
+
+	data, err := json.Marshal(link)
+	if err != nil {
+		return fmt.Errorf("marshaling bead link: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("opening bead links: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing bead links: %w", err)
+	}
+	return nil
+}
+
+// LoadBeadLinks returns the beads linked to each beat, in the order they were
+// linked
+func LoadBeadLinks(beatsDir string) (map[string][]string, error) {
+	links := make(map[string][]string)
+	path := filepath.Join(beatsDir, model.BeadLinksFileName)
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return links, nil
+		}
+		return nil, fmt.Errorf("opening bead links: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var link model.BeadLink
+		if err := json.Unmarshal([]byte(line), &link); err != nil || link.BeadID == "" {
+			continue
+		}
+		links[link.BeatID] = appendUnique(links[link.BeatID], link.BeadID)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading bead links: %w", err)
+	}
+	return links, nil
+}
+
+// BeadsFingerprint identifies the beats' bead links and whether their beads
+// are closed, so ripeness can be rescored when either changes
+func BeadsFingerprint(beats []model.Beat) string {
+	state := make(map[string]interface{})
+	for _, b := range beats {
+		if len(b.LinkedBeads) > 0 {
+			state[b.ID] = []interface{}{b.LinkedBeads, b.BeadsResolved}
+		}
+	}
+	if len(state) == 0 {
+		return ""
+	}
+	// Maps marshal with sorted keys, so equal states give equal fingerprints
+	data, _ := json.Marshal(state)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// BeadSourcesFingerprint identifies the files bead links and statuses are
+// read from by size and modification time: beats.jsonl, the bead link log
+// and the beads project's issues. It is cheap enough to check on every load,
+// so LoadBeats only has to run when one of them changed.
+func BeadSourcesFingerprint(beatsDir string) string {
+	paths := []string{
+		filepath.Join(beatsDir, BeatsFile),
+		filepath.Join(beatsDir, model.BeadLinksFileName),
+	}
+	root := filepath.Dir(beatsDir)
+	if beadsDir, err := FindBeadsDir(root, root); err == nil {
+		for _, name := range beadIssueFiles {
+			paths = append(paths, filepath.Join(beadsDir, name))
+		}
+	}
+
+	h := sha256.New()
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			fmt.Fprintf(h, "%s|%d|%d\n", path, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// mergeBeadLinks adds the logged bead links to the beats' own
+func mergeBeadLinks(beats []model.Beat, links map[string][]string) {
+	if len(links) == 0 {
+		return
+	}
+	for i := range beats {
+		for _, id := range links[beats[i].ID] {
+			beats[i].LinkedBeads = appendUnique(beats[i].LinkedBeads, id)
+		}
+	}
+}
+
+// resolveBeads marks beats whose linked beads are all known and closed
+func resolveBeads(beats []model.Beat, issues map[string]model.BeadStatus) {
+	if len(issues) == 0 {
+		return
+	}
+	for i := range beats {
+		if len(beats[i].LinkedBeads) == 0 {
+			continue
+		}
+		resolved := true
+		for _, id := range beats[i].LinkedBeads {
+			if status, ok := issues[id]; !ok || !status.Closed() {
+				resolved = false
+				break
+			}
+		}
+		beats[i].BeadsResolved = resolved
+	}
+}
+
+// BeadsDir is where bd keeps a project's local data
+const BeadsDir = ".beads"
+
+// beadIssueFiles are the JSONL files bd exports issues to, in the order read
+var beadIssueFiles = []string{"beads.jsonl", "issues.jsonl"}
+
+// FindBeadsDir returns the .beads directory at or above startPath, looking
+// no higher than rootPath. A startPath outside rootPath is the only place
+// looked in.
+func FindBeadsDir(startPath, rootPath string) (string, error) {
+	current, err := filepath.Abs(startPath)
+	if err != nil {
+		return "", err
+	}
+	root, err := filepath.Abs(rootPath)
+	if err != nil {
+		return "", err
+	}
+	for {
+		candidate := filepath.Join(current, BeadsDir)
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			return candidate, nil
+		}
+		parent := filepath.Dir(current)
+		if current == root || parent == current || !withinDir(parent, root) {
+			return "", fmt.Errorf("no %s directory found", BeadsDir)
+		}
+		current = parent
+	}
+}
+
+// withinDir reports whether path is dir or inside it
+func withinDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// LoadBeadIssues reads the issues in a .beads directory, keyed by ID. Where
+// an issue appears more than once, the last line wins.
+func LoadBeadIssues(beadsDir string) (map[string]model.BeadStatus, error) {
+	issues := make(map[string]model.BeadStatus)
+	for _, name := range beadIssueFiles {
+		path := filepath.Join(beadsDir, name)
+		file, err := os.Open(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("opening %s: %w", path, err)
+		}
+
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			var issue model.BeadStatus
+			if err := json.Unmarshal([]byte(line), &issue); err != nil || issue.ID == "" {
+				continue
+			}
+			issues[issue.ID] = issue
+		}
+		err = scanner.Err()
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+	}
+	return issues, nil
+}
+
+// LoadProjectBeadIssues reads the issues of the beads project sharing a beats
+// project's root, where .beads sits beside .beats. A project without one has
+// no issues.
+func LoadProjectBeadIssues(beatsDir string) (map[string]model.BeadStatus, error) {
+	root := filepath.Dir(beatsDir)
+	beadsDir, err := FindBeadsDir(root, root)
+	if err != nil {
+		return map[string]model.BeadStatus{}, nil
+	}
+	return LoadBeadIssues(beadsDir)
+}
+
+// DiscoverBeadStores finds the .beads directories under rootPath, as
+// DiscoverProjects does for beats
+func DiscoverBeadStores(rootPath string) ([]model.BeadStore, error) {
+	var stores []model.BeadStore
+
+	err := filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if info.Name() == "node_modules" || info.Name() == ".git" || info.Name() == "vendor" {
+			return filepath.SkipDir
+		}
+		if info.Name() == BeadsDir {
+			parentDir := filepath.Dir(path)
+			name := filepath.Base(parentDir)
+			if parentDir == rootPath {
+				name = filepath.Base(rootPath)
+			}
+
+			issues, err := LoadBeadIssues(path)
+			if err != nil {
+				return nil
+			}
+
+			stores = append(stores, model.BeadStore{
+				Name:       name,
+				Path:       path,
+				IssueCount: len(issues),
+			})
+			return filepath.SkipDir
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("discovering bead stores: %w", err)
+	}
+
+	sort.Slice(stores, func(i, j int) bool {
+		return stores[i].IssueCount > stores[j].IssueCount
+	})
+
+	return stores, nil
+}
+
+// beatsRewriteAttempts is how many times WriteBeadLinks starts over when
+// beats.jsonl changes while it is being rewritten
+const beatsRewriteAttempts = 5
+
+// WriteBeadLink adds a bead to a beat's linked_beads in beats.jsonl
+func WriteBeadLink(beatsDir, beatID, beadID string) error {
+	return WriteBeadLinks(beatsDir, []model.BeadLink{{BeatID: beatID, BeadID: beadID}})
+}
+
+// WriteBeadLinks adds beads to their beats' linked_beads in beats.jsonl,
+// rewriting the file atomically, once for all of them. Other fields,
+// including ones btv doesn't know, are kept as they are. The beats CLI may
+// append to the file at any time, so the rewrite only replaces it if it is
+// still as read, and starts over otherwise.
+func WriteBeadLinks(beatsDir string, links []model.BeadLink) error {
+	if len(links) == 0 {
+		return nil
+	}
+	path := filepath.Join(beatsDir, BeatsFile)
+	for attempt := 0; attempt < beatsRewriteAttempts; attempt++ {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+		updated := data
+		for _, link := range links {
+			if updated, err = addBeadLink(updated, link.BeatID, link.BeadID); err != nil {
+				return err
+			}
+		}
+
+		tmpPath := path + ".tmp"
+		if err := os.WriteFile(tmpPath, updated, 0644); err != nil {
+			return fmt.Errorf("writing temp beats file: %w", err)
+		}
+		current, err := os.ReadFile(path)
+		if err != nil {
+			os.Remove(tmpPath)
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+		if !bytes.Equal(current, data) {
+			os.Remove(tmpPath)
+			continue
+		}
+		if err := os.Rename(tmpPath, path); err != nil {
+			os.Remove(tmpPath)
+			return fmt.Errorf("renaming beats file: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("%s kept changing while linking beads", BeatsFile)
+}
+
+// addBeadLink returns the contents of beats.jsonl with a bead added to a
+// beat's linked_beads
+func addBeadLink(data []byte, beatID, beadID string) ([]byte, error) {
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(line), &fields); err != nil {
+			continue
+		}
+		var id string
+		if err := json.Unmarshal(fields["id"], &id); err != nil || id != beatID {
+			continue
+		}
+
+		var linked []string
+		raw, ok := fields["linked_beads"]
+		if ok {
+			if err := json.Unmarshal(raw, &linked); err != nil {
+				return nil, fmt.Errorf("decoding linked_beads of %s: %w", beatID, err)
+			}
+		}
+		value, err := json.Marshal(appendUnique(linked, beadID))
+		if err != nil {
+			return nil, fmt.Errorf("marshaling linked_beads: %w", err)
+		}
+
+		// Append the field in place when it is new, so the line keeps the
+		// order beats wrote it in
+		trimmed := strings.TrimRight(line, " \t\r")
+		if !ok && strings.HasSuffix(trimmed, "}") {
+			lines[i] = trimmed[:len(trimmed)-1] + `,"linked_beads":` + string(value) + "}"
+		} else {
+			fields["linked_beads"] = value
+			updated, err := json.Marshal(fields)
+			if err != nil {
+				return nil, fmt.Errorf("marshaling beat %s: %w", beatID, err)
+			}
+			lines[i] = string(updated)
+		}
+		return []byte(strings.Join(lines, "\n")), nil
+	}
+	return nil, fmt.Errorf("beat not found in %s: %s", BeatsFile, beatID)
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, existing := range list {
+		if existing == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
@@ -76,6 +76,41 @@ func ComputeSourceHash(beatsDir string) (string, error) {
 	return hex.EncodeToString(h.Sum(nil))[:16], nil
 }
 
+// sourceSize returns the size of beats.jsonl, 0 if it does not exist
+func sourceSize(beatsDir string) (int64, error) {
+	info, err := os.Stat(filepath.Join(beatsDir, BeatsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("checking beats file: %w", err)
+	}
+	return info.Size(), nil
+}
+
+// onlyAppended reports whether beats.jsonl has grown since the cache was
+// built without its earlier bytes changing, as when beats are added
+func onlyAppended(beatsDir string, cache *model.Cache) bool {
+	if cache.SourceSize <= 0 {
+		return false
+	}
+	file, err := os.Open(filepath.Join(beatsDir, BeatsFile))
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil || info.Size() <= cache.SourceSize {
+		return false
+	}
+
+	h := sha256.New()
+	if _, err := io.CopyN(h, file, cache.SourceSize); err != nil {
+		return false
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16] == cache.SourceHash
+}
+
 // IsCacheValid checks if the cache is valid for the current beats data
 func IsCacheValid(beatsDir string, cache *model.Cache) bool {
 	if cache == nil {
@@ -101,6 +136,12 @@ func LoadOrCreateCache(beatsDir string) (*model.Cache, bool, error) {
 		return nil, false, err
 	}
 
+	if cache != nil && cache.Version != model.CacheVersion && migrateCache(cache) {
+		if err := SaveCache(beatsDir, cache); err != nil {
+			return nil, false, fmt.Errorf("saving migrated cache: %w", err)
+		}
+	}
+
 	if IsCacheValid(beatsDir, cache) {
 		return cache, false, nil
 	}
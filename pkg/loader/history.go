@@ -0,0 +1,51 @@
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bierlingm/beats_viewer/pkg/model"
+)
+
+// LoadRipenessHistory reads the per-beat ripeness events for a project
+func LoadRipenessHistory(beatsDir string) (map[string][]model.RipenessEvent, error) {
+	history := make(map[string][]model.RipenessEvent)
+	path := filepath.Join(beatsDir, model.RipenessHistoryFileName)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return history, nil
+		}
+		return nil, fmt.Errorf("reading ripeness history: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("decoding ripeness history: %w", err)
+	}
+	return history, nil
+}
+
+// SaveRipenessHistory writes the per-beat ripeness events atomically
+func SaveRipenessHistory(beatsDir string, history map[string][]model.RipenessEvent) error {
+	path := filepath.Join(beatsDir, model.RipenessHistoryFileName)
+	tmpPath := path + ".tmp"
+
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling ripeness history: %w", err)
+	}
+
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("writing temp ripeness history: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("renaming ripeness history: %w", err)
+	}
+
+	return nil
+}
@@ -17,6 +17,13 @@ const (
 	BeatsFile = "beats.jsonl"
 )
 
+// Warn reports a problem that doesn't stop beats loading, such as unreadable
+// bead data. It writes to stderr unless replaced, as the TUI does while it
+// owns the terminal.
+var Warn = func(err error) {
+	fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+}
+
 func FindBeatsDir(startPath string) (string, error) {
 	current := startPath
 	for {
@@ -32,6 +39,9 @@ func FindBeatsDir(startPath string) (string, error) {
 	}
 }
 
+// LoadBeats reads a project's beats, newest first, with the beads btv linked
+// to them merged into LinkedBeads and BeadsResolved set from the beads
+// project's data. Bead data that can't be read is left out with a warning.
 func LoadBeats(beatsDir string) ([]model.Beat, error) {
 	filePath := filepath.Join(beatsDir, BeatsFile)
 	file, err := os.Open(filePath)
@@ -69,6 +79,18 @@ func LoadBeats(beatsDir string) ([]model.Beat, error) {
 		return beats[i].CreatedAt.After(beats[j].CreatedAt)
 	})
 
+	// Bead data only adds to the beats, so a problem with it is not fatal
+	if links, err := LoadBeadLinks(beatsDir); err != nil {
+		Warn(fmt.Errorf("leaving out bead links: %w", err))
+	} else {
+		mergeBeadLinks(beats, links)
+	}
+	if issues, err := LoadProjectBeadIssues(beatsDir); err != nil {
+		Warn(fmt.Errorf("leaving out bead statuses: %w", err))
+	} else {
+		resolveBeads(beats, issues)
+	}
+
 	return beats, nil
 }
 
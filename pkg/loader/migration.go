@@ -24,23 +24,29 @@ func MigrateToV02(beatsDir string, progressFn func(step string, current, total i
 		return nil, fmt.Errorf("loading beats: %w", err)
 	}
 
-	sourceHash, err := ComputeSourceHash(beatsDir)
-	if err != nil {
-		return nil, fmt.Errorf("computing source hash: %w", err)
-	}
-
 	cache := model.NewCache()
-	cache.SourceHash = sourceHash
+	if err := setSource(beatsDir, cache); err != nil {
+		return nil, err
+	}
 	cache.GeneratedAt = time.Now()
 
-	progress("Classifying taxonomies", 0, len(beats))
-	for i, beat := range beats {
-		cache.Taxonomies[beat.ID] = taxonomy.Classify(beat)
-		progress("Classifying taxonomies", i+1, len(beats))
+	// Embedding neighbours come from the (slow, optional) clustering step and
+	// stay valid for unchanged beats, and chains are made by hand, so keep
+	// both across rebuilds
+	var chains []model.Chain
+	if previous, err := LoadCache(beatsDir); err == nil && previous != nil {
+		cache.EmbeddingNeighbors = previous.EmbeddingNeighbors
+		chains = previous.Chains
+	}
+
+	if err := classifyTaxonomies(beatsDir, cache, beats, progress); err != nil {
+		return nil, err
 	}
 
 	progress("Extracting entities", 0, len(beats))
-	cache.Entities, cache.EntityIndex = entity.ExtractAll(beats)
+	if err := applyEntityDictionary(beatsDir, cache, beats); err != nil {
+		return nil, err
+	}
 	progress("Extracting entities", len(beats), len(beats))
 
 	progress("Calculating ripeness", 0, len(beats))
@@ -48,11 +54,15 @@ func MigrateToV02(beatsDir string, progressFn func(step string, current, total i
 	for _, beat := range beats {
 		cache.ViewStats[beat.ID] = model.ViewStat{}
 	}
-	cache.Ripeness = ripeness.CalculateAll(beats, cache.ViewStats)
+	if err := applyRipenessProfile(beatsDir, cache, beats); err != nil {
+		return nil, err
+	}
 	progress("Calculating ripeness", len(beats), len(beats))
 
 	cache.Clusters = []model.Cluster{}
-	cache.Chains = []model.Chain{}
+	if chains != nil {
+		cache.Chains = chains
+	}
 	cache.EmbeddingsAvailable = false
 
 	progress("Saving cache", 0, 1)
@@ -64,6 +74,56 @@ func MigrateToV02(beatsDir string, progressFn func(step string, current, total i
 	return cache, nil
 }
 
+// migrateCache upgrades a cache written by an older btv in place, reporting
+// whether it could. Caches it cannot upgrade are rebuilt from scratch, which
+// loses clusters and view counts.
+func migrateCache(cache *model.Cache) bool {
+	switch cache.Version {
+	case "0.2.0":
+		migrateEntityIDs(cache)
+		fallthrough
+	case "0.3.0":
+		// Channels and sources were stored as numbers; they decode to names
+		// (see model.Channel.UnmarshalJSON) and are saved as names from now on
+	default:
+		return false
+	}
+	cache.Version = model.CacheVersion
+	return true
+}
+
+// migrateEntityIDs gives 0.2.0 entities typed IDs and rekeys the entity
+// index by them. The old index was keyed by bare name, merging same-name
+// entities of different types, so it is rebuilt from the entities.
+func migrateEntityIDs(cache *model.Cache) {
+	byID := make(map[string]*model.Entity)
+	seen := make(map[string]bool) // entity ID + beat ID
+	var order []string
+
+	for _, e := range cache.Entities {
+		id := model.EntityID(e.Type, e.Name)
+		merged, ok := byID[id]
+		if !ok {
+			merged = &model.Entity{ID: id, Name: e.Name, Type: e.Type}
+			byID[id] = merged
+			order = append(order, id)
+		}
+		for _, beatID := range e.BeatIDs {
+			if !seen[id+"\x00"+beatID] {
+				seen[id+"\x00"+beatID] = true
+				merged.BeatIDs = append(merged.BeatIDs, beatID)
+			}
+		}
+	}
+
+	cache.Entities = make([]model.Entity, 0, len(order))
+	cache.EntityIndex = make(map[string][]string, len(order))
+	for _, id := range order {
+		cache.Entities = append(cache.Entities, *byID[id])
+		cache.EntityIndex[id] = byID[id].BeatIDs
+	}
+}
+
 // EnsureCache loads existing cache or migrates to create one
 func EnsureCache(beatsDir string, progressFn func(step string, current, total int)) (*model.Cache, error) {
 	cache, needsRebuild, err := LoadOrCreateCache(beatsDir)
@@ -71,13 +131,342 @@ func EnsureCache(beatsDir string, progressFn func(step string, current, total in
 		return nil, err
 	}
 
-	if !needsRebuild && cache != nil {
+	if needsRebuild {
+		if cache, err = appendNewBeats(beatsDir); err != nil {
+			return nil, err
+		}
+	}
+
+	if cache != nil {
+		if err := refreshTaxonomyIfChanged(beatsDir, cache); err != nil {
+			return nil, err
+		}
+		if err := refreshEntitiesIfDictionaryChanged(beatsDir, cache); err != nil {
+			return nil, err
+		}
+		if err := refreshRipenessIfProfileChanged(beatsDir, cache); err != nil {
+			return nil, err
+		}
 		return cache, nil
 	}
 
 	return MigrateToV02(beatsDir, progressFn)
 }
 
+// setSource records which beats.jsonl the cache was built from
+func setSource(beatsDir string, cache *model.Cache) error {
+	size, err := sourceSize(beatsDir)
+	if err != nil {
+		return err
+	}
+	hash, err := ComputeSourceHash(beatsDir)
+	if err != nil {
+		return fmt.Errorf("computing source hash: %w", err)
+	}
+	// A write between the two calls leaves them disagreeing; a size of 0
+	// makes the next load rebuild rather than trust the hash for a prefix
+	if after, err := sourceSize(beatsDir); err != nil || after != size {
+		size = 0
+	}
+	cache.SourceHash, cache.SourceSize = hash, size
+	return nil
+}
+
+// appendNewBeats brings the cache up to date when beats were only appended
+// to beats.jsonl since it was built, enriching just the new beats and
+// keeping clusters, view counts and everything else. Ripeness is rescored
+// for all beats, since new beats can connect to old ones. It returns nil
+// when the cache has to be rebuilt instead.
+func appendNewBeats(beatsDir string) (*model.Cache, error) {
+	cache, err := LoadCache(beatsDir)
+	if err != nil || cache == nil || cache.Version != model.CacheVersion || !onlyAppended(beatsDir, cache) {
+		return nil, nil
+	}
+
+	beats, err := LoadBeats(beatsDir)
+	if err != nil {
+		return nil, fmt.Errorf("loading beats: %w", err)
+	}
+	var added []model.Beat
+	for _, beat := range beats {
+		if _, ok := cache.Taxonomies[beat.ID]; !ok {
+			added = append(added, beat)
+		}
+	}
+
+	if err := classifyNewBeats(beatsDir, cache, beats, added); err != nil {
+		return nil, err
+	}
+	extractor, err := entity.LoadExtractor(beatsDir)
+	if err != nil {
+		return nil, fmt.Errorf("loading entity dictionary: %w", err)
+	}
+	mergeEntities(cache, extractor, added)
+	if llm, ok := extractor.(*entity.LLMExtractor); ok {
+		if err := llm.SaveCache(); err != nil {
+			return nil, err
+		}
+	}
+	if cache.ViewStats == nil {
+		cache.ViewStats = make(map[string]model.ViewStat)
+	}
+	for _, beat := range added {
+		cache.ViewStats[beat.ID] = model.ViewStat{}
+	}
+	if err := applyRipenessProfile(beatsDir, cache, beats); err != nil {
+		return nil, err
+	}
+
+	if err := setSource(beatsDir, cache); err != nil {
+		return nil, err
+	}
+	if err := SaveCache(beatsDir, cache); err != nil {
+		return nil, fmt.Errorf("saving cache: %w", err)
+	}
+	return cache, nil
+}
+
+// mergeEntities extracts the entities of added beats and merges them into
+// the cache's
+func mergeEntities(cache *model.Cache, extractor entity.Extractor, added []model.Beat) {
+	entities, index, mentions := entity.ExtractAllWith(extractor, added)
+	if cache.EntityIndex == nil {
+		cache.EntityIndex = make(map[string][]string)
+	}
+	if cache.Mentions == nil {
+		cache.Mentions = make(map[string][]model.Mention)
+	}
+
+	existing := make(map[string]int, len(cache.Entities))
+	for i, e := range cache.Entities {
+		existing[e.ID] = i
+	}
+	for _, e := range entities {
+		if i, ok := existing[e.ID]; ok {
+			cache.Entities[i].BeatIDs = append(cache.Entities[i].BeatIDs, e.BeatIDs...)
+		} else {
+			existing[e.ID] = len(cache.Entities)
+			cache.Entities = append(cache.Entities, e)
+		}
+	}
+	for id, beatIDs := range index {
+		cache.EntityIndex[id] = append(cache.EntityIndex[id], beatIDs...)
+	}
+	for beatID, m := range mentions {
+		cache.Mentions[beatID] = m
+	}
+}
+
+// classifyTaxonomies classifies every beat with the project's taxonomy
+// definition and what it learned from labeled beats, then applies the manual
+// classifications
+func classifyTaxonomies(beatsDir string, cache *model.Cache, beats []model.Beat, progress func(step string, current, total int)) error {
+	cache.Taxonomies = make(map[string]model.Taxonomy, len(beats))
+	def, overrides, err := classifyBeats(beatsDir, cache, beats, beats, progress)
+	if err != nil {
+		return err
+	}
+	cache.TaxonomyDefinition = def.Fingerprint()
+	cache.TaxonomyOverrides = taxonomy.OverridesFingerprint(overrides)
+	return nil
+}
+
+// classifyNewBeats classifies added beats, learning from all beats, and
+// leaves the others' classifications as they are. The cache keeps the
+// taxonomy fingerprints it had, so a changed taxonomy still reclassifies
+// every beat.
+func classifyNewBeats(beatsDir string, cache *model.Cache, beats, added []model.Beat) error {
+	if cache.Taxonomies == nil {
+		cache.Taxonomies = make(map[string]model.Taxonomy, len(beats))
+	}
+	_, _, err := classifyBeats(beatsDir, cache, beats, added, func(string, int, int) {})
+	return err
+}
+
+// classifyBeats classifies some beats with a classifier trained on the
+// labeled ones among all beats, and returns the taxonomy and manual
+// classifications used
+func classifyBeats(beatsDir string, cache *model.Cache, beats, some []model.Beat, progress func(step string, current, total int)) (*taxonomy.Definition, map[string]model.TaxonomyOverride, error) {
+	def, _, err := taxonomy.LoadDefinition(beatsDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading taxonomy: %w", err)
+	}
+	overrides, err := LoadTaxonomyOverrides(beatsDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	classifier := def.NewClassifier(def.LabeledBeats(beats, overrides))
+
+	progress("Classifying taxonomies", 0, len(some))
+	for i, beat := range some {
+		t := classifier.Classify(beat)
+		if o, ok := overrides[beat.ID]; ok {
+			t = def.ApplyOverride(t, o)
+		}
+		cache.Taxonomies[beat.ID] = t
+		progress("Classifying taxonomies", i+1, len(some))
+	}
+	return def, overrides, nil
+}
+
+// refreshTaxonomyIfChanged reclassifies a valid cache when the taxonomy
+// definition or the manual classifications, which the classifier learns
+// from, changed since it was built
+func refreshTaxonomyIfChanged(beatsDir string, cache *model.Cache) error {
+	def, _, err := taxonomy.LoadDefinition(beatsDir)
+	if err != nil {
+		return fmt.Errorf("loading taxonomy: %w", err)
+	}
+	overrides, err := LoadTaxonomyOverrides(beatsDir)
+	if err != nil {
+		return err
+	}
+	if cache.TaxonomyDefinition == def.Fingerprint() && cache.TaxonomyOverrides == taxonomy.OverridesFingerprint(overrides) {
+		return nil
+	}
+
+	beats, err := LoadBeats(beatsDir)
+	if err != nil {
+		return fmt.Errorf("loading beats: %w", err)
+	}
+	if err := classifyTaxonomies(beatsDir, cache, beats, func(string, int, int) {}); err != nil {
+		return err
+	}
+	return SaveCache(beatsDir, cache)
+}
+
+// applyEntityDictionary extracts entities with the project's effective
+// dictionary, or its LLM extractor when one is configured
+func applyEntityDictionary(beatsDir string, cache *model.Cache, beats []model.Beat) error {
+	extractor, err := entity.LoadExtractor(beatsDir)
+	if err != nil {
+		return fmt.Errorf("loading entity dictionary: %w", err)
+	}
+	return extractEntities(cache, extractor, beats)
+}
+
+func extractEntities(cache *model.Cache, extractor entity.Extractor, beats []model.Beat) error {
+	cache.Entities, cache.EntityIndex, cache.Mentions = entity.ExtractAllWith(extractor, beats)
+	cache.EntityDictionary = extractor.Fingerprint()
+	if llm, ok := extractor.(*entity.LLMExtractor); ok {
+		llm.ForgetMissing(beats)
+		if err := llm.SaveCache(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refreshEntitiesIfDictionaryChanged re-extracts entities when a dictionary
+// config was edited since the cache was built, or the LLM extractor has beats
+// to retry. Connections depend on entities, so it also rescores ripeness and
+// saves the cache.
+func refreshEntitiesIfDictionaryChanged(beatsDir string, cache *model.Cache) error {
+	extractor, err := entity.LoadExtractor(beatsDir)
+	if err != nil {
+		return fmt.Errorf("loading entity dictionary: %w", err)
+	}
+	if cache.EntityDictionary == extractor.Fingerprint() {
+		llm, ok := extractor.(*entity.LLMExtractor)
+		if !ok || !llm.Pending() {
+			return nil
+		}
+	}
+
+	beats, err := LoadBeats(beatsDir)
+	if err != nil {
+		return fmt.Errorf("loading beats: %w", err)
+	}
+	if err := extractEntities(cache, extractor, beats); err != nil {
+		return err
+	}
+	if err := applyRipenessProfile(beatsDir, cache, beats); err != nil {
+		return err
+	}
+	return SaveCache(beatsDir, cache)
+}
+
+// RipenessRefreshInterval is how long cached ripeness stays current; scores
+// depend on elapsed time, so a valid cache is still rescored this often
+const RipenessRefreshInterval = 24 * time.Hour
+
+// applyRipenessProfile scores all beats with the project's effective profile
+// and records lifecycle transitions in the ripeness history
+func applyRipenessProfile(beatsDir string, cache *model.Cache, beats []model.Beat) error {
+	profile, _, err := ripeness.LoadProfile(beatsDir)
+	if err != nil {
+		return fmt.Errorf("loading ripeness profile: %w", err)
+	}
+
+	now := time.Now()
+	conns := ripeness.BuildConnections(beats, cache.Entities, cache.EmbeddingNeighbors, profile.SimilarityThreshold)
+	breakdowns := profile.CalculateAllWithBreakdown(beats, cache.ViewStats, conns)
+
+	cache.Ripeness = make(map[string]float64, len(breakdowns))
+	cache.RipenessStates = make(map[string]model.RipenessState, len(breakdowns))
+	for id, b := range breakdowns {
+		cache.Ripeness[id] = b.Total
+		cache.RipenessStates[id] = b.State
+	}
+	cache.RipenessProfile = profile.Fingerprint()
+	cache.RipenessTiers = profile.Tiers
+	cache.RipenessAt = now
+	cache.BeadLinks = BeadsFingerprint(beats)
+	cache.BeadSources = BeadSourcesFingerprint(beatsDir)
+
+	history, err := LoadRipenessHistory(beatsDir)
+	if err != nil {
+		return err
+	}
+	if ripeness.RecordHistory(history, breakdowns, now) > 0 {
+		if err := SaveRipenessHistory(beatsDir, history); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RescoreRipeness scores all beats again, for when something the scores
+// depend on, such as embedding neighbours, changed in the cache. The caller
+// saves the cache.
+func RescoreRipeness(beatsDir string, cache *model.Cache) error {
+	beats, err := LoadBeats(beatsDir)
+	if err != nil {
+		return fmt.Errorf("loading beats: %w", err)
+	}
+	return applyRipenessProfile(beatsDir, cache, beats)
+}
+
+// refreshRipenessIfProfileChanged rescores a valid cache when the ripeness
+// profile was edited or beads were linked or closed since it was built, or when the
+// scores are older than RipenessRefreshInterval, without redoing the other
+// steps. Beats and bead data are only read when their files changed.
+func refreshRipenessIfProfileChanged(beatsDir string, cache *model.Cache) error {
+	profile, _, err := ripeness.LoadProfile(beatsDir)
+	if err != nil {
+		return fmt.Errorf("loading ripeness profile: %w", err)
+	}
+	current := cache.RipenessProfile == profile.Fingerprint() && time.Since(cache.RipenessAt) < RipenessRefreshInterval
+	sources := BeadSourcesFingerprint(beatsDir)
+	if current && cache.BeadSources == sources {
+		return nil
+	}
+
+	beats, err := LoadBeats(beatsDir)
+	if err != nil {
+		return fmt.Errorf("loading beats: %w", err)
+	}
+	if !current || cache.BeadLinks != BeadsFingerprint(beats) {
+		if err := applyRipenessProfile(beatsDir, cache, beats); err != nil {
+			return err
+		}
+	}
+	// Record the files as they were before reading, so a change made since
+	// is picked up next time
+	cache.BeadSources = sources
+	return SaveCache(beatsDir, cache)
+}
+
 // LoadEnrichedBeats loads beats with their computed fields from cache
 func LoadEnrichedBeats(beatsDir string, progressFn func(step string, current, total int)) ([]model.EnrichedBeat, *model.Cache, error) {
 	beats, err := LoadBeats(beatsDir)
@@ -112,8 +501,10 @@ func LoadEnrichedBeats(beatsDir string, progressFn func(step string, current, to
 			Beat:          beat,
 			Taxonomy:      cache.Taxonomies[beat.ID],
 			RipenessScore: cache.Ripeness[beat.ID],
+			RipenessState: cache.RipenessStates[beat.ID],
 			ClusterID:     clusterIndex[beat.ID],
 			ChainIDs:      chainIndex[beat.ID],
+			Mentions:      cache.Mentions[beat.ID],
 		}
 
 		if viewStat, ok := cache.ViewStats[beat.ID]; ok {
@@ -0,0 +1,66 @@
+package loader
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bierlingm/beats_viewer/pkg/model"
+)
+
+// AppendReviewDecision appends a stale-review decision to the project's review log
+func AppendReviewDecision(beatsDir string, decision model.ReviewDecision) error {
+	path := filepath.Join(beatsDir, model.ReviewLogFileName)
+
+	data, err := json.Marshal(decision)
+	if err != nil {
+		return fmt.Errorf("marshaling review decision: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("opening review log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing review log: %w", err)
+	}
+	return nil
+}
+
+// LoadReviewDecisions returns the latest review decision per beat
+func LoadReviewDecisions(beatsDir string) (map[string]model.ReviewDecision, error) {
+	decisions := make(map[string]model.ReviewDecision)
+	path := filepath.Join(beatsDir, model.ReviewLogFileName)
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return decisions, nil
+		}
+		return nil, fmt.Errorf("opening review log: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var d model.ReviewDecision
+		if err := json.Unmarshal([]byte(line), &d); err != nil {
+			continue
+		}
+		decisions[d.BeatID] = d
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading review log: %w", err)
+	}
+	return decisions, nil
+}
@@ -0,0 +1,85 @@
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bierlingm/beats_viewer/pkg/model"
+	"github.com/bierlingm/beats_viewer/pkg/taxonomy"
+)
+
+// LoadTaxonomyOverrides returns the manual classifications keyed by beat ID
+func LoadTaxonomyOverrides(beatsDir string) (map[string]model.TaxonomyOverride, error) {
+	overrides := make(map[string]model.TaxonomyOverride)
+	path := filepath.Join(beatsDir, model.TaxonomyOverridesFileName)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return overrides, nil
+		}
+		return nil, fmt.Errorf("reading taxonomy overrides: %w", err)
+	}
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("parsing taxonomy overrides: %w", err)
+	}
+	return overrides, nil
+}
+
+// SaveTaxonomyOverrides writes the manual classifications atomically
+func SaveTaxonomyOverrides(beatsDir string, overrides map[string]model.TaxonomyOverride) error {
+	path := filepath.Join(beatsDir, model.TaxonomyOverridesFileName)
+	tmpPath := path + ".tmp"
+
+	data, err := json.MarshalIndent(overrides, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling taxonomy overrides: %w", err)
+	}
+
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("writing temp taxonomy overrides: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("renaming taxonomy overrides: %w", err)
+	}
+
+	return nil
+}
+
+// SetTaxonomyOverride records a manual classification for a beat, replacing
+// any earlier one. An override with neither channel nor source removes it.
+func SetTaxonomyOverride(beatsDir, beatID string, o model.TaxonomyOverride) error {
+	def, _, err := taxonomy.LoadDefinition(beatsDir)
+	if err != nil {
+		return fmt.Errorf("loading taxonomy: %w", err)
+	}
+	if o.Channel != "" {
+		ch, err := def.ParseChannel(o.Channel)
+		if err != nil {
+			return err
+		}
+		o.Channel = ch.String()
+	}
+	if o.Source != "" {
+		src, err := def.ParseSource(o.Source)
+		if err != nil {
+			return err
+		}
+		o.Source = src.String()
+	}
+
+	overrides, err := LoadTaxonomyOverrides(beatsDir)
+	if err != nil {
+		return err
+	}
+	if o.Channel == "" && o.Source == "" {
+		delete(overrides, beatID)
+	} else {
+		overrides[beatID] = o
+	}
+	return SaveTaxonomyOverrides(beatsDir, overrides)
+}
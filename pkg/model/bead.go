@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// BeadLink records a bead created from, or linked to, a beat
+type BeadLink struct {
+	BeatID string    `json:"beat_id"`
+	BeadID string    `json:"bead_id"`
+	At     time.Time `json:"at"`
+}
+
+// BeadLinksFileName logs bead links alongside beats.jsonl, so they survive
+// without rewriting the file beats owns
+const BeadLinksFileName = "btv-beads.jsonl"
+
+// BeadStatus is what the beads project knows about a linked bead
+type BeadStatus struct {
+	ID       string `json:"id"`
+	Title    string `json:"title,omitempty"`
+	Status   string `json:"status"`   // e.g. open, in_progress, blocked, closed
+	Priority int    `json:"priority"` // 0 (highest) to 4
+}
+
+// Closed reports whether the bead's work is done
+func (s BeadStatus) Closed() bool {
+	return s.Status == "closed"
+}
+
+// BeadStore is a beads project's local data directory
+type BeadStore struct {
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+	IssueCount int    `json:"issue_count"`
+}
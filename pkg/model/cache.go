@@ -13,72 +13,115 @@ type Cache struct {
 	Version     string    `json:"version"`
 	GeneratedAt time.Time `json:"generated_at"`
 	SourceHash  string    `json:"source_hash"`
+	SourceSize  int64     `json:"source_size,omitempty"` // bytes of beats.jsonl SourceHash covers
 
-	Taxonomies  map[string]Taxonomy `json:"taxonomies"`
-	Entities    []Entity            `json:"entities"`
-	EntityIndex map[string][]string `json:"entity_index"`
-	Ripeness    map[string]float64  `json:"ripeness"`
-	Clusters    []Cluster           `json:"clusters"`
-	Chains      []Chain             `json:"chains"`
-	ViewStats   map[string]ViewStat `json:"view_stats"`
+	Taxonomies  map[string]Taxonomy  `json:"taxonomies"`
+	Entities    []Entity             `json:"entities"`
+	EntityIndex map[string][]string  `json:"entity_index"`       // entity ID -> beat IDs
+	Mentions    map[string][]Mention `json:"mentions,omitempty"` // beat ID -> entity mention spans
+	Ripeness    map[string]float64   `json:"ripeness"`
+	Clusters    []Cluster            `json:"clusters"`
+	Chains      []Chain              `json:"chains"`
+	ViewStats   map[string]ViewStat  `json:"view_stats"`
 
-	EmbeddingsAvailable bool `json:"embeddings_available"`
+	EntityDictionary   string `json:"entity_dictionary,omitempty"`   // fingerprint of the dictionary entities were extracted with
+	TaxonomyDefinition string `json:"taxonomy_definition,omitempty"` // fingerprint of the taxonomy beats were classified with
+	TaxonomyOverrides  string `json:"taxonomy_overrides,omitempty"`  // fingerprint of the manual classifications applied
+
+	EmbeddingsAvailable bool                  `json:"embeddings_available"`
+	EmbeddingNeighbors  map[string][]Neighbor `json:"embedding_neighbors,omitempty"`
+
+	RipenessProfile string                   `json:"ripeness_profile,omitempty"`
+	RipenessTiers   RipenessThresholds       `json:"ripeness_tiers"`
+	RipenessStates  map[string]RipenessState `json:"ripeness_states"`
+	RipenessAt      time.Time                `json:"ripeness_at"`
+	BeadLinks       string                   `json:"bead_links,omitempty"`   // fingerprint of the bead links and closed beads ripeness was scored with
+	BeadSources     string                   `json:"bead_sources,omitempty"` // fingerprint of the files those were read from
 }
 
-const CacheVersion = "0.2.0"
+const CacheVersion = "0.4.0"
 const CacheFileName = "btv-cache.json"
 
 // NewCache creates a new empty cache
 func NewCache() *Cache {
 	return &Cache{
-		Version:     CacheVersion,
-		GeneratedAt: time.Now(),
-		Taxonomies:  make(map[string]Taxonomy),
-		Entities:    []Entity{},
-		EntityIndex: make(map[string][]string),
-		Ripeness:    make(map[string]float64),
-		Clusters:    []Cluster{},
-		Chains:      []Chain{},
-		ViewStats:   make(map[string]ViewStat),
+		Version:        CacheVersion,
+		GeneratedAt:    time.Now(),
+		Taxonomies:     make(map[string]Taxonomy),
+		Entities:       []Entity{},
+		EntityIndex:    make(map[string][]string),
+		Ripeness:       make(map[string]float64),
+		RipenessStates: make(map[string]RipenessState),
+		Clusters:       []Cluster{},
+		Chains:         []Chain{},
+		ViewStats:      make(map[string]ViewStat),
+
+		RipenessTiers: DefaultRipenessThresholds,
 	}
 }
 
 // EnrichedBeat holds a beat with its computed fields from cache
 type EnrichedBeat struct {
 	Beat
-	Taxonomy          Taxonomy  `json:"-"`
-	ExtractedEntities []Entity  `json:"-"`
-	RipenessScore     float64   `json:"-"`
-	ClusterID         string    `json:"-"`
-	ChainIDs          []string  `json:"-"`
-	ViewCount         int       `json:"-"`
-	LastViewedAt      *time.Time `json:"-"`
+	Taxonomy          Taxonomy      `json:"-"`
+	ExtractedEntities []Entity      `json:"-"`
+	Mentions          []Mention     `json:"-"`
+	RipenessScore     float64       `json:"-"`
+	RipenessState     RipenessState `json:"-"`
+	ClusterID         string        `json:"-"`
+	ChainIDs          []string      `json:"-"`
+	ViewCount         int           `json:"-"`
+	LastViewedAt      *time.Time    `json:"-"`
 }
 
-// RipenessTier returns the ripeness tier for a score
-func RipenessTier(score float64) string {
+// RipenessThresholds are the lower bounds of each ripeness tier
+type RipenessThresholds struct {
+	Maturing float64 `json:"maturing"`
+	Ripe     float64 `json:"ripe"`
+	Overripe float64 `json:"overripe"`
+}
+
+// DefaultRipenessThresholds are the tier cutoffs used when no profile overrides them
+var DefaultRipenessThresholds = RipenessThresholds{
+	Maturing: 0.3,
+	Ripe:     0.6,
+	Overripe: 0.8,
+}
+
+// Tier returns the ripeness tier for a score
+func (t RipenessThresholds) Tier(score float64) string {
 	switch {
-	case score >= 0.8:
+	case score >= t.Overripe:
 		return "Overripe"
-	case score >= 0.6:
+	case score >= t.Ripe:
 		return "Ripe"
-	case score >= 0.3:
+	case score >= t.Maturing:
 		return "Maturing"
 	default:
 		return "Fresh"
 	}
 }
 
-// RipenessEmoji returns an emoji indicator for a ripeness score
-func RipenessEmoji(score float64) string {
+// Emoji returns an emoji indicator for a score
+func (t RipenessThresholds) Emoji(score float64) string {
 	switch {
-	case score >= 0.8:
+	case score >= t.Overripe:
 		return "🔴"
-	case score >= 0.6:
+	case score >= t.Ripe:
 		return "🟢"
-	case score >= 0.3:
+	case score >= t.Maturing:
 		return "🟡"
 	default:
 		return "⚪"
 	}
 }
+
+// RipenessTier returns the ripeness tier for a score
+func RipenessTier(score float64) string {
+	return DefaultRipenessThresholds.Tier(score)
+}
+
+// RipenessEmoji returns an emoji indicator for a ripeness score
+func RipenessEmoji(score float64) string {
+	return DefaultRipenessThresholds.Emoji(score)
+}
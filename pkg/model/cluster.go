@@ -21,3 +21,9 @@ type Chain struct {
 	CreatedAt     time.Time `json:"created_at"`
 	RipenessScore float64   `json:"ripeness"`
 }
+
+// Neighbor is a semantically similar beat found through embeddings
+type Neighbor struct {
+	BeatID     string  `json:"beat_id"`
+	Similarity float64 `json:"similarity"`
+}
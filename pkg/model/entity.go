@@ -1,5 +1,10 @@
 package model
 
+import (
+	"fmt"
+	"strings"
+)
+
 // EntityType represents the type of extracted entity
 type EntityType int
 
@@ -28,6 +33,16 @@ func (e EntityType) String() string {
 	}
 }
 
+// ParseEntityType parses a type name such as "person" or "Tool"
+func ParseEntityType(s string) (EntityType, error) {
+	for _, t := range AllEntityTypes() {
+		if strings.EqualFold(s, t.String()) {
+			return t, nil
+		}
+	}
+	return EntityPerson, fmt.Errorf("unknown entity type %q", s)
+}
+
 // AllEntityTypes returns all valid entity types
 func AllEntityTypes() []EntityType {
 	return []EntityType{
@@ -39,9 +54,43 @@ func AllEntityTypes() []EntityType {
 	}
 }
 
+// EntityID identifies an entity by type and case-folded name, e.g.
+// "organization:factory", so same-name entities of different types stay apart
+func EntityID(t EntityType, name string) string {
+	return strings.ToLower(t.String()) + ":" + strings.ToLower(name)
+}
+
+// ParseEntityID splits an entity ID into its type and lowercased name
+func ParseEntityID(id string) (EntityType, string, error) {
+	typeName, name, ok := strings.Cut(id, ":")
+	if !ok || name == "" {
+		return EntityPerson, "", fmt.Errorf("invalid entity ID %q (want type:name)", id)
+	}
+	t, err := ParseEntityType(typeName)
+	if err != nil {
+		return EntityPerson, "", fmt.Errorf("invalid entity ID %q: %w", id, err)
+	}
+	return t, name, nil
+}
+
 // Entity represents an extracted entity from beats
 type Entity struct {
+	ID      string     `json:"id"` // see EntityID
 	Name    string     `json:"name"`
 	Type    EntityType `json:"type"`
 	BeatIDs []string   `json:"beat_ids"`
 }
+
+// Mention is one occurrence of an entity in a beat's content
+type Mention struct {
+	Name    string     `json:"name"` // canonical entity name
+	Type    EntityType `json:"type"`
+	Start   int        `json:"start"`   // byte offset into Content
+	End     int        `json:"end"`     // byte offset just past the mention
+	Surface string     `json:"surface"` // text as written, which may be an alias
+}
+
+// EntityID returns the ID of the entity mentioned
+func (m Mention) EntityID() string {
+	return EntityID(m.Type, m.Name)
+}
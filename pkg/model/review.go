@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// ReviewOutcome is the decision taken on a beat during stale review
+type ReviewOutcome string
+
+const (
+	OutcomeKeep    ReviewOutcome = "keep"
+	OutcomeArchive ReviewOutcome = "archive"
+	OutcomeConvert ReviewOutcome = "convert"
+	OutcomeChain   ReviewOutcome = "chain"
+	OutcomeDelete  ReviewOutcome = "delete"
+)
+
+// ReviewDecision records a stale-review decision for a beat
+type ReviewDecision struct {
+	BeatID  string        `json:"beat_id"`
+	Outcome ReviewOutcome `json:"outcome"`
+	At      time.Time     `json:"at"`
+}
+
+// ReviewLogFileName stores review decisions alongside beats.jsonl
+const ReviewLogFileName = "btv-reviews.jsonl"
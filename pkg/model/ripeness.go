@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// RipenessState is a beat's position in the ripeness lifecycle
+type RipenessState string
+
+const (
+	StateFresh    RipenessState = "fresh"
+	StateMaturing RipenessState = "maturing"
+	StateRipe     RipenessState = "ripe"
+	StateOverripe RipenessState = "overripe"
+	StateCompost  RipenessState = "compost"
+	StateResolved RipenessState = "resolved" // acted on: every linked bead is closed
+)
+
+// AllRipenessStates returns the lifecycle states in order
+func AllRipenessStates() []RipenessState {
+	return []RipenessState{
+		StateFresh,
+		StateMaturing,
+		StateRipe,
+		StateOverripe,
+		StateCompost,
+		StateResolved,
+	}
+}
+
+// Emoji returns an indicator for the state, matching RipenessEmoji's palette
+func (s RipenessState) Emoji() string {
+	switch s {
+	case StateOverripe:
+		return "🔴"
+	case StateRipe:
+		return "🟢"
+	case StateMaturing:
+		return "🟡"
+	case StateCompost:
+		return "🟤"
+	case StateResolved:
+		return "✅"
+	default:
+		return "⚪"
+	}
+}
+
+// RipenessEvent records a beat's ripeness at a point in time
+type RipenessEvent struct {
+	At    time.Time     `json:"at"`
+	Score float64       `json:"score"`
+	State RipenessState `json:"state"`
+}
+
+// RipenessHistoryFileName stores ripeness events alongside beats.jsonl; it is
+// kept apart from the cache so history survives cache rebuilds
+const RipenessHistoryFileName = "btv-ripeness-history.json"
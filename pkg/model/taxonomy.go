@@ -1,41 +1,53 @@
 package model
 
-// Channel represents the primary classification of a beat
-type Channel int
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Channel is the primary classification of a beat, by name. The built-in
+// channels below can be extended from config; see taxonomy.Definition.
+type Channel string
 
 const (
-	ChannelUnknown Channel = iota
-	ChannelCoaching     // Insights from coaching/mentoring
-	ChannelResearch     // Deliberate investigation
-	ChannelDiscovery    // Serendipitous finding
-	ChannelDevelopment  // Building/coding insight
-	ChannelReflection   // Personal synthesis
-	ChannelReference    // Saved for later use
-	ChannelMilestone    // Achievement/completion
+	ChannelUnknown     Channel = ""
+	ChannelCoaching    Channel = "Coaching"    // Insights from coaching/mentoring
+	ChannelResearch    Channel = "Research"    // Deliberate investigation
+	ChannelDiscovery   Channel = "Discovery"   // Serendipitous finding
+	ChannelDevelopment Channel = "Development" // Building/coding insight
+	ChannelReflection  Channel = "Reflection"  // Personal synthesis
+	ChannelReference   Channel = "Reference"   // Saved for later use
+	ChannelMilestone   Channel = "Milestone"   // Achievement/completion
 )
 
 func (c Channel) String() string {
-	switch c {
-	case ChannelCoaching:
-		return "Coaching"
-	case ChannelResearch:
-		return "Research"
-	case ChannelDiscovery:
-		return "Discovery"
-	case ChannelDevelopment:
-		return "Development"
-	case ChannelReflection:
-		return "Reflection"
-	case ChannelReference:
-		return "Reference"
-	case ChannelMilestone:
-		return "Milestone"
-	default:
+	if c == ChannelUnknown {
 		return "Unknown"
 	}
+	return string(c)
 }
 
-// AllChannels returns all valid channels
+// UnmarshalJSON reads a channel name, or the number caches before 0.4.0
+// stored, which indexed the built-in channels
+func (c *Channel) UnmarshalJSON(data []byte) error {
+	var n int
+	if err := json.Unmarshal(data, &n); err == nil {
+		*c = ChannelUnknown
+		if channels := AllChannels(); n >= 1 && n <= len(channels) {
+			*c = channels[n-1]
+		}
+		return nil
+	}
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("decoding channel: %w", err)
+	}
+	*c = Channel(name)
+	return nil
+}
+
+// AllChannels returns the built-in channels
 func AllChannels() []Channel {
 	return []Channel{
 		ChannelCoaching,
@@ -48,42 +60,48 @@ func AllChannels() []Channel {
 	}
 }
 
-// Source represents the origin type of a beat
-type Source int
+// Source is the origin type of a beat, by name. Like channels, sources can
+// be extended from config.
+type Source string
 
 const (
-	SourceUnknown Source = iota
-	SourceConversation // Human dialogue
-	SourceWeb          // Browser discovery
-	SourceTwitter      // X/Twitter
-	SourceGitHub       // Code/issues/discussions
-	SourceBook         // Reading
-	SourceSession      // Agent/droid session
-	SourceInternal     // Self-generated
+	SourceUnknown      Source = ""
+	SourceConversation Source = "Conversation" // Human dialogue
+	SourceWeb          Source = "Web"          // Browser discovery
+	SourceTwitter      Source = "Twitter"      // X/Twitter
+	SourceGitHub       Source = "GitHub"       // Code/issues/discussions
+	SourceBook         Source = "Book"         // Reading
+	SourceSession      Source = "Session"      // Agent/droid session
+	SourceInternal     Source = "Internal"     // Self-generated
 )
 
 func (s Source) String() string {
-	switch s {
-	case SourceConversation:
-		return "Conversation"
-	case SourceWeb:
-		return "Web"
-	case SourceTwitter:
-		return "Twitter"
-	case SourceGitHub:
-		return "GitHub"
-	case SourceBook:
-		return "Book"
-	case SourceSession:
-		return "Session"
-	case SourceInternal:
-		return "Internal"
-	default:
+	if s == SourceUnknown {
 		return "Unknown"
 	}
+	return string(s)
+}
+
+// UnmarshalJSON reads a source name, or the number caches before 0.4.0
+// stored, which indexed the built-in sources
+func (s *Source) UnmarshalJSON(data []byte) error {
+	var n int
+	if err := json.Unmarshal(data, &n); err == nil {
+		*s = SourceUnknown
+		if sources := AllSources(); n >= 1 && n <= len(sources) {
+			*s = sources[n-1]
+		}
+		return nil
+	}
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("decoding source: %w", err)
+	}
+	*s = Source(name)
+	return nil
 }
 
-// AllSources returns all valid sources
+// AllSources returns the built-in sources
 func AllSources() []Source {
 	return []Source{
 		SourceConversation,
@@ -96,9 +114,76 @@ func AllSources() []Source {
 	}
 }
 
-// Taxonomy represents the classification of a beat
+// LabelScore is a channel or source and its share of a beat's
+// classification scores
+type LabelScore struct {
+	Name  string  `json:"name"`
+	Score float64 `json:"score"`
+}
+
+// Taxonomy represents the classification of a beat. Channel and Source are
+// the best labels; a beat also carries the others that scored well enough,
+// so one about shipping a feature after a coaching call can be both
+// Development and Coaching.
 type Taxonomy struct {
-	Channel    Channel `json:"channel"`
-	Source     Source  `json:"source"`
-	Confidence float64 `json:"confidence"`
+	Channel           Channel      `json:"channel"`
+	Source            Source       `json:"source"`
+	Confidence        float64      `json:"confidence"`                   // from the margin over the runner-up labels
+	ChannelConfidence float64      `json:"channel_confidence,omitempty"` // the channel's and source's, averaged above
+	SourceConfidence  float64      `json:"source_confidence,omitempty"`
+	Manual            bool         `json:"manual,omitempty"`   // both channel and source set by hand
+	Channels          []LabelScore `json:"channels,omitempty"` // ranked, scores summing to 1; empty when nothing matched
+	Sources           []LabelScore `json:"sources,omitempty"`
+	SecondaryChannels []Channel    `json:"secondary_channels,omitempty"` // runners-up above the label threshold
+	SecondarySources  []Source     `json:"secondary_sources,omitempty"`
 }
+
+// HasChannel reports whether ch is the beat's channel or one of its
+// secondary channels
+func (t Taxonomy) HasChannel(ch Channel) bool {
+	if t.Channel == ch {
+		return true
+	}
+	for _, c := range t.SecondaryChannels {
+		if c == ch {
+			return true
+		}
+	}
+	return false
+}
+
+// HasSource reports whether src is the beat's source or one of its
+// secondary sources
+func (t Taxonomy) HasSource(src Source) bool {
+	if t.Source == src {
+		return true
+	}
+	for _, s := range t.SecondarySources {
+		if s == src {
+			return true
+		}
+	}
+	return false
+}
+
+// ChannelLabels returns the beat's channel followed by its secondary channels
+func (t Taxonomy) ChannelLabels() []Channel {
+	return append([]Channel{t.Channel}, t.SecondaryChannels...)
+}
+
+// SourceLabels returns the beat's source followed by its secondary sources
+func (t Taxonomy) SourceLabels() []Source {
+	return append([]Source{t.Source}, t.SecondarySources...)
+}
+
+// TaxonomyOverride is a channel and/or source set by hand for a beat. An
+// empty field keeps the automatic classification.
+type TaxonomyOverride struct {
+	Channel string    `json:"channel,omitempty"`
+	Source  string    `json:"source,omitempty"`
+	At      time.Time `json:"at"`
+}
+
+// TaxonomyOverridesFileName stores manual classifications, keyed by beat ID,
+// alongside beats.jsonl
+const TaxonomyOverridesFileName = "btv-taxonomy.json"
@@ -22,6 +22,10 @@ type Beat struct {
 	Entities    []string  `json:"entities,omitempty"`
 	References  []string  `json:"references,omitempty"`
 	LinkedBeads []string  `json:"linked_beads,omitempty"`
+
+	// BeadsResolved is set on load when every linked bead is closed. It is
+	// derived, so it is never written with the beat.
+	BeadsResolved bool `json:"-"`
 }
 
 type Project struct {
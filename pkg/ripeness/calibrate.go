@@ -0,0 +1,281 @@
+package ripeness
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/bierlingm/beats_viewer/pkg/model"
+)
+
+// FactorNames lists the ripeness factors in Signals order
+var FactorNames = []string{"age", "revisit", "connection", "action", "completeness"}
+
+const numFactors = 5
+
+// Calibration outcome labels
+const (
+	LabelBead     = "bead"
+	LabelArchived = "archived"
+)
+
+// MinSamplesPerClass is the fewest positive and negative samples calibration accepts
+const MinSamplesPerClass = 5
+
+const (
+	calibrationIterations = 2000
+	calibrationRate       = 0.1
+	calibrationL2         = 0.01
+)
+
+// Sample is one beat with a known outcome, described by its raw factor signals
+type Sample struct {
+	BeatID   string
+	Label    string
+	Positive bool
+	Signals  [numFactors]float64
+}
+
+// FactorImportance reports how one factor relates to beats becoming beads
+type FactorImportance struct {
+	Factor         string  `json:"factor"`
+	Coefficient    float64 `json:"coefficient"` // on standardized signals
+	Importance     float64 `json:"importance"`  // share of total absolute coefficient
+	Weight         float64 `json:"weight"`
+	PreviousWeight float64 `json:"previous_weight"`
+}
+
+// Calibration is the result of fitting factor weights to outcomes
+type Calibration struct {
+	Profile          Profile            `json:"profile"`
+	Samples          int                `json:"samples"`
+	ByLabel          map[string]int     `json:"by_label"`
+	Intercept        float64            `json:"intercept"`
+	TrainingAccuracy float64            `json:"training_accuracy"`
+	LogLoss          float64            `json:"log_loss"`
+	Factors          []FactorImportance `json:"factors"`
+}
+
+// Signals returns the unweighted 0.0-1.0 value of each factor for a beat.
+// Linked beads are left out because they are the outcome being predicted.
+func (p Profile) Signals(beat model.Beat, conns Connections, viewStat model.ViewStat) [numFactors]float64 {
+	ageDays := time.Since(beat.CreatedAt).Hours() / 24
+	completeness := CalculateCompleteness(CompletenessFactors{
+		HasEntities:    len(beat.Entities) > 0,
+		HasGoodImpetus: hasGoodImpetus(beat),
+		HasReferences:  len(beat.References) > 0,
+		ContentLength:  len(beat.Content),
+	})
+
+	return [numFactors]float64{
+		math.Min(ageDays/p.Ramps.AgeDays, 1.0),
+		math.Min(float64(viewStat.ViewCount)/p.Ramps.Views, 1.0),
+		math.Min(float64(conns.Count(beat.ID))/p.Ramps.Connections, 1.0),
+		math.Min(float64(countActionPhrases(beat.Content))/p.Ramps.ActionHits, 1.0),
+		completeness,
+	}
+}
+
+// BuildSamples labels beats by what happened to them: converted to a bead
+// (linked beads or a convert decision) or dismissed (archive or delete
+// decision). Beats with neither outcome are left out rather than labelled by
+// how long they sat idle, which is what the age and revisit factors measure.
+func (p Profile) BuildSamples(beats []model.Beat, viewStats map[string]model.ViewStat, conns Connections, decisions map[string]model.ReviewDecision) []Sample {
+	var samples []Sample
+	for _, beat := range beats {
+		decision := decisions[beat.ID]
+
+		var label string
+		switch {
+		case len(beat.LinkedBeads) > 0 || decision.Outcome == model.OutcomeConvert:
+			label = LabelBead
+		case decision.Outcome == model.OutcomeArchive || decision.Outcome == model.OutcomeDelete:
+			label = LabelArchived
+		default:
+			continue
+		}
+
+		samples = append(samples, Sample{
+			BeatID:   beat.ID,
+			Label:    label,
+			Positive: label == LabelBead,
+			Signals:  p.Signals(beat, conns, viewStats[beat.ID]),
+		})
+	}
+	return samples
+}
+
+// Calibrate fits a logistic regression of "became a bead" on the factor
+// signals and turns the positive coefficients into normalized weights. The
+// returned profile keeps p's ramps, decay and tiers.
+func (p Profile) Calibrate(samples []Sample) (Calibration, error) {
+	result := Calibration{ByLabel: make(map[string]int), Samples: len(samples)}
+
+	positives := 0
+	for _, s := range samples {
+		result.ByLabel[s.Label]++
+		if s.Positive {
+			positives++
+		}
+	}
+	negatives := len(samples) - positives
+	if positives < MinSamplesPerClass || negatives < MinSamplesPerClass {
+		return result, fmt.Errorf("need at least %d beats that became beads and %d that did not (have %d and %d)",
+			MinSamplesPerClass, MinSamplesPerClass, positives, negatives)
+	}
+
+	var mean, std [numFactors]float64
+	for _, s := range samples {
+		for i, v := range s.Signals {
+			mean[i] += v
+		}
+	}
+	for i := range mean {
+		mean[i] /= float64(len(samples))
+	}
+	for _, s := range samples {
+		for i, v := range s.Signals {
+			std[i] += (v - mean[i]) * (v - mean[i])
+		}
+	}
+	for i := range std {
+		std[i] = math.Sqrt(std[i] / float64(len(samples)))
+	}
+
+	standardized := make([][numFactors]float64, len(samples))
+	for n, s := range samples {
+		for i, v := range s.Signals {
+			if std[i] > 0 {
+				standardized[n][i] = (v - mean[i]) / std[i]
+			}
+		}
+	}
+
+	var coef [numFactors]float64
+	var intercept float64
+	count := float64(len(samples))
+
+	for iter := 0; iter < calibrationIterations; iter++ {
+		var grad [numFactors]float64
+		var gradIntercept float64
+
+		for n, x := range standardized {
+			errTerm := sigmoid(intercept+dotFactors(coef, x)) - boolToFloat(samples[n].Positive)
+			gradIntercept += errTerm
+			for i := range grad {
+				grad[i] += errTerm * x[i]
+			}
+		}
+
+		intercept -= calibrationRate * gradIntercept / count
+		for i := range coef {
+			coef[i] -= calibrationRate * (grad[i]/count + calibrationL2*coef[i])
+		}
+	}
+
+	correct := 0
+	var logLoss float64
+	for n, x := range standardized {
+		prob := sigmoid(intercept + dotFactors(coef, x))
+		if (prob >= 0.5) == samples[n].Positive {
+			correct++
+		}
+		prob = math.Min(math.Max(prob, 1e-9), 1-1e-9)
+		if samples[n].Positive {
+			logLoss -= math.Log(prob)
+		} else {
+			logLoss -= math.Log(1 - prob)
+		}
+	}
+	result.TrainingAccuracy = float64(correct) / count
+	result.LogLoss = logLoss / count
+	result.Intercept = intercept
+
+	// Coefficients on raw signals are what a weighted sum of signals can use
+	var raw [numFactors]float64
+	var rawSum, absSum float64
+	for i := range coef {
+		if std[i] > 0 && coef[i] > 0 {
+			raw[i] = coef[i] / std[i]
+			rawSum += raw[i]
+		}
+		absSum += math.Abs(coef[i])
+	}
+	if rawSum == 0 {
+		return result, fmt.Errorf("no factor is positively associated with beats becoming beads")
+	}
+
+	previous := p.weightSlice()
+	var weights [numFactors]float64
+	for i := range raw {
+		weights[i] = math.Round(raw[i]/rawSum*1000) / 1000
+	}
+	// Absorb rounding so the weights still sum to exactly 1.0
+	largest := 0
+	var sum float64
+	for i, w := range weights {
+		sum += w
+		if w > weights[largest] {
+			largest = i
+		}
+	}
+	weights[largest] += 1.0 - sum
+
+	for i, name := range FactorNames {
+		importance := 0.0
+		if absSum > 0 {
+			importance = math.Abs(coef[i]) / absSum
+		}
+		result.Factors = append(result.Factors, FactorImportance{
+			Factor:         name,
+			Coefficient:    coef[i],
+			Importance:     importance,
+			Weight:         weights[i],
+			PreviousWeight: previous[i],
+		})
+	}
+	sort.Slice(result.Factors, func(i, j int) bool {
+		return result.Factors[i].Importance > result.Factors[j].Importance
+	})
+
+	tuned := p
+	tuned.Name = "calibrated"
+	tuned.Weights = Weights{
+		Age:          weights[0],
+		Revisit:      weights[1],
+		Connection:   weights[2],
+		Action:       weights[3],
+		Completeness: weights[4],
+	}
+	if err := tuned.Validate(); err != nil {
+		return result, fmt.Errorf("calibrated profile invalid: %w", err)
+	}
+	result.Profile = tuned
+
+	return result, nil
+}
+
+func (p Profile) weightSlice() [numFactors]float64 {
+	w := p.Weights
+	return [numFactors]float64{w.Age, w.Revisit, w.Connection, w.Action, w.Completeness}
+}
+
+func sigmoid(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
+
+func dotFactors(a, b [numFactors]float64) float64 {
+	var sum float64
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,132 @@
+package ripeness
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/bierlingm/beats_viewer/pkg/model"
+)
+
+// MaxRelatedBeats caps how many related beats are counted per beat
+const MaxRelatedBeats = 5
+
+// Relation explains why another beat counts as connected
+type Relation struct {
+	BeatID     string  `json:"beat_id"`
+	Entity     string  `json:"entity,omitempty"`     // shared entity, if related by entity
+	Similarity float64 `json:"similarity,omitempty"` // cosine similarity, if related by embedding
+}
+
+// Connections maps beat IDs to the beats they relate to
+type Connections map[string][]Relation
+
+// Count returns the number of related beats for a beat
+func (c Connections) Count(beatID string) int {
+	return len(c[beatID])
+}
+
+// BuildConnections precomputes related beats for every beat from shared
+// entities (the extracted entities plus each beat's own Entities field)
+// and, when provided, embedding neighbours at or above similarityThreshold.
+//
+// Each beat stops collecting relations at MaxRelatedBeats, and entities
+// present in more than half of a corpus of ten or more beats are ignored as
+// too common to signal a connection, so the cost stays roughly linear in the
+// number of beats.
+func BuildConnections(beats []model.Beat, entities []model.Entity, neighbors map[string][]model.Neighbor, similarityThreshold float64) Connections {
+	beatEntities := make(map[string][]string)
+	entityBeats := make(map[string][]string)
+	displayNames := make(map[string]string)
+	mentioned := make(map[string]bool)
+
+	addMention := func(key, entityName, beatID string) {
+		if mentioned[key+"\x00"+beatID] {
+			return
+		}
+		mentioned[key+"\x00"+beatID] = true
+		if _, ok := displayNames[key]; !ok {
+			displayNames[key] = entityName
+		}
+		entityBeats[key] = append(entityBeats[key], beatID)
+		beatEntities[beatID] = append(beatEntities[beatID], key)
+	}
+
+	idsByName := make(map[string][]string)
+	for _, e := range entities {
+		idsByName[strings.ToLower(e.Name)] = append(idsByName[strings.ToLower(e.Name)], e.ID)
+		for _, id := range e.BeatIDs {
+			addMention(e.ID, e.Name, id)
+		}
+	}
+	// A beat's own entities are untyped, so they join every extracted entity
+	// with the same name, or are keyed by name alone
+	for _, beat := range beats {
+		for _, name := range beat.Entities {
+			ids := idsByName[strings.ToLower(name)]
+			if len(ids) == 0 {
+				addMention(strings.ToLower(name), name, beat.ID)
+			}
+			for _, id := range ids {
+				addMention(id, name, beat.ID)
+			}
+		}
+	}
+
+	commonLimit := len(beats)
+	if len(beats) >= 10 {
+		commonLimit = len(beats) / 2
+	}
+
+	result := make(Connections, len(beats))
+	for _, beat := range beats {
+		seen := map[string]bool{beat.ID: true}
+		var relations []Relation
+
+		for _, n := range neighbors[beat.ID] {
+			if len(relations) >= MaxRelatedBeats {
+				break
+			}
+			if n.Similarity < similarityThreshold || seen[n.BeatID] {
+				continue
+			}
+			seen[n.BeatID] = true
+			relations = append(relations, Relation{BeatID: n.BeatID, Similarity: n.Similarity})
+		}
+
+		entities := beatEntities[beat.ID]
+		// Rarest entities first; ties by key so truncation is the same every run
+		sort.Slice(entities, func(i, j int) bool {
+			ni, nj := len(entityBeats[entities[i]]), len(entityBeats[entities[j]])
+			if ni != nj {
+				return ni < nj
+			}
+			return entities[i] < entities[j]
+		})
+
+		for _, key := range entities {
+			if len(relations) >= MaxRelatedBeats {
+				break
+			}
+			ids := entityBeats[key]
+			if len(ids) > commonLimit {
+				continue
+			}
+			for _, id := range ids {
+				if len(relations) >= MaxRelatedBeats {
+					break
+				}
+				if seen[id] {
+					continue
+				}
+				seen[id] = true
+				relations = append(relations, Relation{BeatID: id, Entity: displayNames[key]})
+			}
+		}
+
+		if len(relations) > 0 {
+			result[beat.ID] = relations
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,210 @@
+package ripeness
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats_viewer/pkg/model"
+)
+
+// FactorScore is one factor's contribution against its maximum
+type FactorScore struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+	Max   float64 `json:"max"`
+}
+
+// Explanation describes in plain language why a beat has its ripeness
+type Explanation struct {
+	Breakdown RipenessBreakdown `json:"breakdown"`
+	Factors   []FactorScore     `json:"factors"`
+	Reasons   []string          `json:"reasons"`
+	Boost     string            `json:"boost,omitempty"`  // factor that would most increase ripeness
+	Advice    string            `json:"advice,omitempty"` // what to do about it
+	Phrases   []string          `json:"phrases,omitempty"`
+}
+
+// MatchedActionPhrases returns the action phrases found in content
+func MatchedActionPhrases(content string) []string {
+	lower := strings.ToLower(content)
+	var matched []string
+	for _, pattern := range actionPatterns {
+		if strings.Contains(lower, pattern) {
+			matched = append(matched, pattern)
+		}
+	}
+	return matched
+}
+
+// ActionPhraseSpans returns the byte ranges of every action phrase occurrence
+// in content, sorted and with overlaps merged
+func ActionPhraseSpans(content string) [][2]int {
+	lower := strings.ToLower(content)
+	if len(lower) != len(content) {
+		// Lowercasing changed byte widths, so offsets would not line up
+		return nil
+	}
+	var spans [][2]int
+	for _, pattern := range actionPatterns {
+		offset := 0
+		for {
+			idx := strings.Index(lower[offset:], pattern)
+			if idx < 0 {
+				break
+			}
+			start := offset + idx
+			spans = append(spans, [2]int{start, start + len(pattern)})
+			offset = start + len(pattern)
+		}
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+
+	var merged [][2]int
+	for _, s := range spans {
+		if n := len(merged); n > 0 && s[0] <= merged[n-1][1] {
+			if s[1] > merged[n-1][1] {
+				merged[n-1][1] = s[1]
+			}
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// Explain breaks a beat's ripeness into factor bars, reasons and the most
+// promising way to raise it
+func (p Profile) Explain(beat model.Beat, conns Connections, viewStat model.ViewStat) Explanation {
+	b := p.CalculateWithBreakdown(beat, conns, viewStat)
+	phrases := MatchedActionPhrases(beat.Content)
+
+	exp := Explanation{
+		Breakdown: b,
+		Phrases:   phrases,
+		Factors: []FactorScore{
+			{Name: "age", Value: b.Age, Max: p.Weights.Age},
+			{Name: "revisit", Value: b.Revisit, Max: p.Weights.Revisit},
+			{Name: "connection", Value: b.Connection, Max: p.Weights.Connection},
+			{Name: "action", Value: b.Action, Max: p.Weights.Action},
+			{Name: "completeness", Value: b.Completeness, Max: p.Weights.Completeness},
+		},
+	}
+
+	if beat.BeadsResolved {
+		exp.Reasons = append(exp.Reasons, "resolved: its beads are closed")
+	}
+	ageDays := int(time.Since(beat.CreatedAt).Hours() / 24)
+	exp.Reasons = append(exp.Reasons, fmt.Sprintf("%d days old", ageDays))
+	if b.Decay < 1.0 {
+		exp.Reasons = append(exp.Reasons, fmt.Sprintf("idle %d days, decaying to %.0f%%", int(b.IdleDays), b.Decay*100))
+	}
+
+	switch viewStat.ViewCount {
+	case 0:
+		exp.Reasons = append(exp.Reasons, "never viewed")
+	case 1:
+		exp.Reasons = append(exp.Reasons, "viewed once")
+	default:
+		exp.Reasons = append(exp.Reasons, fmt.Sprintf("viewed %d times", viewStat.ViewCount))
+	}
+
+	exp.Reasons = append(exp.Reasons, connectionReasons(beat, conns[beat.ID])...)
+
+	if len(phrases) > 0 {
+		quoted := make([]string, len(phrases))
+		for i, ph := range phrases {
+			quoted[i] = "'" + ph + "'"
+		}
+		exp.Reasons = append(exp.Reasons, "contains "+strings.Join(quoted, ", "))
+	} else {
+		exp.Reasons = append(exp.Reasons, "no action language")
+	}
+
+	if !hasGoodImpetus(beat) {
+		exp.Reasons = append(exp.Reasons, "generic impetus")
+	}
+	if len(beat.References) == 0 {
+		exp.Reasons = append(exp.Reasons, "no references")
+	}
+
+	if !beat.BeadsResolved {
+		exp.Boost, exp.Advice = bestBoost(exp.Factors, b.Decay < 1.0)
+	}
+	return exp
+}
+
+func connectionReasons(beat model.Beat, relations []Relation) []string {
+	var reasons []string
+
+	if n := len(beat.LinkedBeads); n == 1 {
+		reasons = append(reasons, "linked to 1 bead")
+	} else if n > 1 {
+		reasons = append(reasons, fmt.Sprintf("linked to %d beads", n))
+	}
+
+	byEntity := make(map[string]int)
+	var order []string
+	similar := 0
+	for _, r := range relations {
+		if r.Entity == "" {
+			similar++
+			continue
+		}
+		if byEntity[r.Entity] == 0 {
+			order = append(order, r.Entity)
+		}
+		byEntity[r.Entity]++
+	}
+
+	for _, name := range order {
+		if byEntity[name] == 1 {
+			reasons = append(reasons, fmt.Sprintf("1 related beat shares %s", name))
+		} else {
+			reasons = append(reasons, fmt.Sprintf("%d related beats share %s", byEntity[name], name))
+		}
+	}
+	if similar == 1 {
+		reasons = append(reasons, "1 semantically similar beat")
+	} else if similar > 1 {
+		reasons = append(reasons, fmt.Sprintf("%d semantically similar beats", similar))
+	}
+
+	if len(reasons) == 0 {
+		reasons = append(reasons, "no related beats")
+	}
+	return reasons
+}
+
+var boostAdvice = map[string]string{
+	"revisit":      "revisit it",
+	"connection":   "link it to related beats or a bead",
+	"action":       "write down a concrete next step",
+	"completeness": "add references or a specific impetus",
+}
+
+// bestBoost picks the actionable factor with the most headroom. Age is left
+// out since only time changes it.
+func bestBoost(factors []FactorScore, decaying bool) (string, string) {
+	best := ""
+	bestGap := 0.0
+	for _, f := range factors {
+		if f.Name == "age" {
+			continue
+		}
+		if gap := f.Max - f.Value; gap > bestGap+1e-9 {
+			best = f.Name
+			bestGap = gap
+		}
+	}
+
+	if decaying {
+		return "revisit", "revisit it to stop the decay"
+	}
+	if best == "" {
+		return "", ""
+	}
+	return best, fmt.Sprintf("%s (+%.2f)", boostAdvice[best], bestGap)
+}
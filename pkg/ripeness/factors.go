@@ -14,6 +14,14 @@ var actionPatterns = []string{
 
 // DetectActionLanguage returns a score 0.0-1.0 based on actionable phrasing
 func DetectActionLanguage(content string) float64 {
+	score := float64(countActionPhrases(content)) / DefaultActionRamp
+	if score > 1.0 {
+		score = 1.0
+	}
+	return score
+}
+
+func countActionPhrases(content string) int {
 	lower := strings.ToLower(content)
 	matches := 0
 	for _, pattern := range actionPatterns {
@@ -21,11 +29,7 @@ func DetectActionLanguage(content string) float64 {
 			matches++
 		}
 	}
-	score := float64(matches) / 3.0
-	if score > 1.0 {
-		score = 1.0
-	}
-	return score
+	return matches
 }
 
 // Completeness factors for scoring
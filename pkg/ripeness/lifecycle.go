@@ -0,0 +1,101 @@
+package ripeness
+
+import (
+	"math"
+	"time"
+
+	"github.com/bierlingm/beats_viewer/pkg/model"
+)
+
+const (
+	// HistoryScoreDelta is the minimum score change recorded without a state change
+	HistoryScoreDelta = 0.05
+	// MaxHistoryEvents caps the events kept per beat
+	MaxHistoryEvents = 100
+)
+
+// idleDays returns the days since the beat last saw any activity
+func idleDays(beat model.Beat, viewStat model.ViewStat) float64 {
+	last := beat.CreatedAt
+	if beat.UpdatedAt.After(last) {
+		last = beat.UpdatedAt
+	}
+	if viewStat.LastViewedAt != nil && viewStat.LastViewedAt.After(last) {
+		last = *viewStat.LastViewedAt
+	}
+	return time.Since(last).Hours() / 24
+}
+
+// decayMultiplier returns the factor applied to a beat's score. A beat only
+// decays once it has finished its age ramp (peaked) and has then sat idle for
+// longer than the decay window. Revisiting resets the idle clock; a fully
+// connected beat does not decay at all.
+func (p Profile) decayMultiplier(createdAt time.Time, idle float64, connections int) float64 {
+	if p.Decay.AfterDays <= 0 {
+		return 1.0
+	}
+	if float64(connections) >= p.Ramps.Connections {
+		return 1.0
+	}
+	ageDays := time.Since(createdAt).Hours() / 24
+	if ageDays < p.Ramps.AgeDays || idle <= p.Decay.AfterDays {
+		return 1.0
+	}
+	return math.Pow(0.5, (idle-p.Decay.AfterDays)/p.Decay.HalfLifeDays)
+}
+
+// State maps a score to its lifecycle state. peak is the score before decay.
+// A decaying beat whose peak reached the ripe tier is past it: it stays
+// overripe until it falls below the compost threshold. One that never ripened
+// keeps the state of its score.
+func (p Profile) State(score, peak float64) model.RipenessState {
+	if score < peak && peak >= p.Tiers.Ripe {
+		if score < p.Decay.CompostBelow {
+			return model.StateCompost
+		}
+		return model.StateOverripe
+	}
+	switch {
+	case score >= p.Tiers.Overripe:
+		return model.StateOverripe
+	case score >= p.Tiers.Ripe:
+		return model.StateRipe
+	case score >= p.Tiers.Maturing:
+		return model.StateMaturing
+	default:
+		return model.StateFresh
+	}
+}
+
+// CalculateAllWithBreakdown computes full breakdowns for all beats
+func (p Profile) CalculateAllWithBreakdown(beats []model.Beat, viewStats map[string]model.ViewStat, conns Connections) map[string]RipenessBreakdown {
+	result := make(map[string]RipenessBreakdown)
+	for _, beat := range beats {
+		result[beat.ID] = p.CalculateWithBreakdown(beat, conns, viewStats[beat.ID])
+	}
+	return result
+}
+
+// RecordHistory appends an event for every beat whose state changed or whose
+// score moved by at least HistoryScoreDelta since its last recorded event.
+// It returns the number of events added.
+func RecordHistory(history map[string][]model.RipenessEvent, breakdowns map[string]RipenessBreakdown, at time.Time) int {
+	added := 0
+	for beatID, b := range breakdowns {
+		events := history[beatID]
+		if len(events) > 0 {
+			last := events[len(events)-1]
+			if last.State == b.State && math.Abs(last.Score-b.Total) < HistoryScoreDelta {
+				continue
+			}
+		}
+
+		events = append(events, model.RipenessEvent{At: at, Score: b.Total, State: b.State})
+		if len(events) > MaxHistoryEvents {
+			events = events[len(events)-MaxHistoryEvents:]
+		}
+		history[beatID] = events
+		added++
+	}
+	return added
+}
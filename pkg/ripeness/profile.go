@@ -0,0 +1,184 @@
+package ripeness
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/bierlingm/beats_viewer/pkg/config"
+	"github.com/bierlingm/beats_viewer/pkg/model"
+)
+
+// ConfigSection is the key of the ripeness profile in btv config files
+const ConfigSection = "ripeness"
+
+// Default ramps: the input value at which a factor reaches its full weight
+const (
+	DefaultAgeRampDays      = 30
+	DefaultRevisitRampViews = 5
+	DefaultConnectionRamp   = 3
+	DefaultActionRamp       = 3
+)
+
+// Default decay: how long a beat may sit idle before its score starts to fall
+const (
+	DefaultDecayAfterDays   = 60
+	DefaultDecayHalfLife    = 30
+	DefaultCompostThreshold = 0.15
+)
+
+// DefaultSimilarityThreshold is the cosine similarity at which an embedding
+// neighbour counts as a connection
+const DefaultSimilarityThreshold = 0.8
+
+// Weights sets how much each factor contributes to the total score
+type Weights struct {
+	Age          float64 `json:"age"`
+	Revisit      float64 `json:"revisit"`
+	Connection   float64 `json:"connection"`
+	Action       float64 `json:"action"`
+	Completeness float64 `json:"completeness"`
+}
+
+// Sum returns the total of all weights
+func (w Weights) Sum() float64 {
+	return w.Age + w.Revisit + w.Connection + w.Action + w.Completeness
+}
+
+// Ramps sets the input value at which each factor saturates
+type Ramps struct {
+	AgeDays     float64 `json:"age_days"`
+	Views       float64 `json:"views"`
+	Connections float64 `json:"connections"`
+	ActionHits  float64 `json:"action_hits"`
+}
+
+// Decay sets how a peaked beat loses ripeness while nobody touches it.
+// AfterDays of zero disables decay.
+type Decay struct {
+	AfterDays    float64 `json:"after_days"`
+	HalfLifeDays float64 `json:"half_life_days"`
+	CompostBelow float64 `json:"compost_below"`
+}
+
+// Profile is a complete ripeness model: weights, ramps, decay and tier cutoffs
+type Profile struct {
+	Name    string                   `json:"name"`
+	Weights Weights                  `json:"weights"`
+	Ramps   Ramps                    `json:"ramps"`
+	Decay   Decay                    `json:"decay"`
+	Tiers   model.RipenessThresholds `json:"tiers"`
+
+	SimilarityThreshold float64 `json:"similarity_threshold"`
+}
+
+// DefaultProfile returns the built-in ripeness model
+func DefaultProfile() Profile {
+	return Profile{
+		Name: "default",
+		Weights: Weights{
+			Age:          AgeFactor,
+			Revisit:      RevisitFactor,
+			Connection:   ConnectionFactor,
+			Action:       ActionFactor,
+			Completeness: CompletenessFactor,
+		},
+		Ramps: Ramps{
+			AgeDays:     DefaultAgeRampDays,
+			Views:       DefaultRevisitRampViews,
+			Connections: DefaultConnectionRamp,
+			ActionHits:  DefaultActionRamp,
+		},
+		Decay: Decay{
+			AfterDays:    DefaultDecayAfterDays,
+			HalfLifeDays: DefaultDecayHalfLife,
+			CompostBelow: DefaultCompostThreshold,
+		},
+		Tiers: model.DefaultRipenessThresholds,
+
+		SimilarityThreshold: DefaultSimilarityThreshold,
+	}
+}
+
+// Validate checks that the profile describes a usable model
+func (p Profile) Validate() error {
+	w := p.Weights
+	for name, v := range map[string]float64{
+		"age": w.Age, "revisit": w.Revisit, "connection": w.Connection,
+		"action": w.Action, "completeness": w.Completeness,
+	} {
+		if v < 0 {
+			return fmt.Errorf("weight %s must not be negative (got %.3f)", name, v)
+		}
+	}
+	if sum := w.Sum(); math.Abs(sum-1.0) > 0.01 {
+		return fmt.Errorf("weights must sum to 1.0 (got %.3f)", sum)
+	}
+
+	r := p.Ramps
+	for name, v := range map[string]float64{
+		"age_days": r.AgeDays, "views": r.Views,
+		"connections": r.Connections, "action_hits": r.ActionHits,
+	} {
+		if v <= 0 {
+			return fmt.Errorf("ramp %s must be positive (got %.3f)", name, v)
+		}
+	}
+
+	t := p.Tiers
+	if !(0 < t.Maturing && t.Maturing < t.Ripe && t.Ripe < t.Overripe && t.Overripe <= 1) {
+		return fmt.Errorf("tiers must satisfy 0 < maturing < ripe < overripe <= 1 (got %.2f/%.2f/%.2f)",
+			t.Maturing, t.Ripe, t.Overripe)
+	}
+
+	if p.SimilarityThreshold <= 0 || p.SimilarityThreshold > 1 {
+		return fmt.Errorf("similarity_threshold must be in (0, 1] (got %.2f)", p.SimilarityThreshold)
+	}
+
+	d := p.Decay
+	if d.AfterDays < 0 {
+		return fmt.Errorf("decay after_days must not be negative (got %.3f)", d.AfterDays)
+	}
+	if d.AfterDays > 0 && d.HalfLifeDays <= 0 {
+		return fmt.Errorf("decay half_life_days must be positive (got %.3f)", d.HalfLifeDays)
+	}
+	if d.CompostBelow < 0 || d.CompostBelow > t.Maturing {
+		return fmt.Errorf("decay compost_below must be between 0 and the maturing tier (got %.2f)", d.CompostBelow)
+	}
+
+	return nil
+}
+
+// scoringVersion changes with how scores and states are computed, so caches
+// scored by an older btv are rescored
+const scoringVersion = "2"
+
+// Fingerprint returns a short hash identifying the profile's parameters
+func (p Profile) Fingerprint() string {
+	data, _ := json.Marshal(p)
+	sum := sha256.Sum256(append([]byte(scoringVersion), data...))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// LoadProfile returns the effective ripeness profile for a project: the
+// defaults, overlaid by the global config, overlaid by the project config.
+// It also returns the config files that contributed.
+func LoadProfile(beatsDir string) (Profile, []string, error) {
+	profile := DefaultProfile()
+
+	sources, err := config.LoadSection(beatsDir, ConfigSection, &profile)
+	if err != nil {
+		return DefaultProfile(), sources, err
+	}
+	if len(sources) > 0 && profile.Name == "default" {
+		profile.Name = "custom"
+	}
+
+	if err := profile.Validate(); err != nil {
+		return DefaultProfile(), sources, fmt.Errorf("invalid ripeness profile: %w", err)
+	}
+
+	return profile, sources, nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/bierlingm/beats_viewer/pkg/model"
 )
 
+// Default factor weights; a loaded Profile may override them
 const (
 	AgeFactor        = 0.2
 	RevisitFactor    = 0.25
@@ -16,78 +17,70 @@ const (
 	CompletenessFactor = 0.1
 )
 
-// Calculate computes the ripeness score (0.0-1.0) for a beat
+// Calculate computes the ripeness score (0.0-1.0) for a beat with the default
+// profile, relating it to other beats through their Entities fields
 func Calculate(beat model.Beat, allBeats []model.Beat, viewStat model.ViewStat) float64 {
-	age := calculateAgeFactor(beat.CreatedAt)
-	revisit := calculateRevisitFactor(viewStat.ViewCount)
-	connection := calculateConnectionFactor(beat, allBeats)
-	action := DetectActionLanguage(beat.Content) * ActionFactor
-	completeness := calculateCompletenessFactor(beat)
-
-	score := age + revisit + connection + action + completeness
+	conns := BuildConnections(allBeats, nil, nil, 0)
+	return DefaultProfile().Calculate(beat, conns, viewStat)
+}
 
-	if score > 1.0 {
-		score = 1.0
-	}
-	return score
+// Calculate computes the ripeness score (0.0-1.0) for a beat
+func (p Profile) Calculate(beat model.Beat, conns Connections, viewStat model.ViewStat) float64 {
+	return p.CalculateWithBreakdown(beat, conns, viewStat).Total
 }
 
-func calculateAgeFactor(createdAt time.Time) float64 {
+func (p Profile) calculateAgeFactor(createdAt time.Time) float64 {
 	ageDays := time.Since(createdAt).Hours() / 24
-	factor := math.Min(ageDays/30, 1.0) * AgeFactor
+	factor := math.Min(ageDays/p.Ramps.AgeDays, 1.0) * p.Weights.Age
 	return factor
 }
 
-func calculateRevisitFactor(viewCount int) float64 {
-	factor := math.Min(float64(viewCount)/5, 1.0) * RevisitFactor
+func (p Profile) calculateRevisitFactor(viewCount int) float64 {
+	factor := math.Min(float64(viewCount)/p.Ramps.Views, 1.0) * p.Weights.Revisit
 	return factor
 }
 
-func calculateConnectionFactor(beat model.Beat, allBeats []model.Beat) float64 {
-	connections := len(beat.LinkedBeads)
-	connections += countRelatedBeats(beat, allBeats)
-	factor := math.Min(float64(connections)/3, 1.0) * ConnectionFactor
+func (p Profile) connectionFactor(connections int) float64 {
+	factor := math.Min(float64(connections)/p.Ramps.Connections, 1.0) * p.Weights.Connection
 	return factor
 }
 
-func countRelatedBeats(beat model.Beat, allBeats []model.Beat) int {
-	count := 0
-	for _, entity := range beat.Entities {
-		for _, other := range allBeats {
-			if other.ID == beat.ID {
-				continue
-			}
-			for _, otherEntity := range other.Entities {
-				if strings.EqualFold(entity, otherEntity) {
-					count++
-					break
-				}
-			}
-		}
-	}
-	if count > 5 {
-		count = 5
-	}
-	return count
+func (p Profile) calculateActionFactor(content string) float64 {
+	factor := math.Min(float64(countActionPhrases(content))/p.Ramps.ActionHits, 1.0) * p.Weights.Action
+	return factor
+}
+
+func countConnections(beat model.Beat, conns Connections) int {
+	return len(beat.LinkedBeads) + conns.Count(beat.ID)
 }
 
-func calculateCompletenessFactor(beat model.Beat) float64 {
+func (p Profile) calculateCompletenessFactor(beat model.Beat) float64 {
 	factors := CompletenessFactors{
 		HasEntities:    len(beat.Entities) > 0,
-		HasGoodImpetus: beat.Impetus.Label != "" && strings.ToLower(beat.Impetus.Label) != "manual entry",
+		HasGoodImpetus: hasGoodImpetus(beat),
 		HasReferences:  len(beat.References) > 0,
 		HasLinkedBeads: len(beat.LinkedBeads) > 0,
 		ContentLength:  len(beat.Content),
 	}
-	return CalculateCompleteness(factors) * CompletenessFactor
+	return CalculateCompleteness(factors) * p.Weights.Completeness
 }
 
-// CalculateAll computes ripeness scores for all beats
+func hasGoodImpetus(beat model.Beat) bool {
+	return beat.Impetus.Label != "" && strings.ToLower(beat.Impetus.Label) != "manual entry"
+}
+
+// CalculateAll computes ripeness scores for all beats with the default profile
 func CalculateAll(beats []model.Beat, viewStats map[string]model.ViewStat) map[string]float64 {
+	conns := BuildConnections(beats, nil, nil, 0)
+	return DefaultProfile().CalculateAll(beats, viewStats, conns)
+}
+
+// CalculateAll computes ripeness scores for all beats
+func (p Profile) CalculateAll(beats []model.Beat, viewStats map[string]model.ViewStat, conns Connections) map[string]float64 {
 	result := make(map[string]float64)
 	for _, beat := range beats {
 		stat := viewStats[beat.ID]
-		result[beat.ID] = Calculate(beat, beats, stat)
+		result[beat.ID] = p.Calculate(beat, conns, stat)
 	}
 	return result
 }
@@ -100,21 +93,53 @@ type RipenessBreakdown struct {
 	Connection  float64 `json:"connection"`
 	Action      float64 `json:"action"`
 	Completeness float64 `json:"completeness"`
+
+	Decay    float64             `json:"decay"`     // multiplier applied to the factor sum, 1.0 when not decaying
+	IdleDays float64             `json:"idle_days"` // days since the beat was created, updated or viewed
+	State    model.RipenessState `json:"state"`
 }
 
 // CalculateWithBreakdown returns both the score and its component factors
+// using the default profile
 func CalculateWithBreakdown(beat model.Beat, allBeats []model.Beat, viewStat model.ViewStat) RipenessBreakdown {
-	age := calculateAgeFactor(beat.CreatedAt)
-	revisit := calculateRevisitFactor(viewStat.ViewCount)
-	connection := calculateConnectionFactor(beat, allBeats)
-	action := DetectActionLanguage(beat.Content) * ActionFactor
-	completeness := calculateCompletenessFactor(beat)
+	conns := BuildConnections(allBeats, nil, nil, 0)
+	return DefaultProfile().CalculateWithBreakdown(beat, conns, viewStat)
+}
+
+// CalculateWithBreakdown returns both the score and its component factors
+func (p Profile) CalculateWithBreakdown(beat model.Beat, conns Connections, viewStat model.ViewStat) RipenessBreakdown {
+	connections := countConnections(beat, conns)
+
+	age := p.calculateAgeFactor(beat.CreatedAt)
+	revisit := p.calculateRevisitFactor(viewStat.ViewCount)
+	connection := p.connectionFactor(connections)
+	action := p.calculateActionFactor(beat.Content)
+	completeness := p.calculateCompletenessFactor(beat)
 
 	total := age + revisit + connection + action + completeness
 	if total > 1.0 {
 		total = 1.0
 	}
 
+	idle := idleDays(beat, viewStat)
+	decay := p.decayMultiplier(beat.CreatedAt, idle, connections)
+	peak := total
+	total *= decay
+
+	// A beat whose beads are all closed has been acted on
+	if beat.BeadsResolved {
+		return RipenessBreakdown{
+			Age:          age,
+			Revisit:      revisit,
+			Connection:   connection,
+			Action:       action,
+			Completeness: completeness,
+			Decay:        decay,
+			IdleDays:     idle,
+			State:        model.StateResolved,
+		}
+	}
+
 	return RipenessBreakdown{
 		Total:       total,
 		Age:         age,
@@ -122,5 +147,8 @@ func CalculateWithBreakdown(beat model.Beat, allBeats []model.Beat, viewStat mod
 		Connection:  connection,
 		Action:      action,
 		Completeness: completeness,
+		Decay:       decay,
+		IdleDays:    idle,
+		State:       p.State(total, peak),
 	}
 }
@@ -0,0 +1,280 @@
+package taxonomy
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/bierlingm/beats_viewer/pkg/model"
+)
+
+// NaiveBayes is a multinomial naive Bayes text classifier with add-one
+// smoothing
+type NaiveBayes struct {
+	classes    []string // sorted, so ties break the same way every time
+	docs       map[string]int
+	words      map[string]map[string]int
+	totalWords map[string]int
+	vocab      map[string]bool
+	total      int
+}
+
+// Example is a text with the label it was given
+type Example struct {
+	Text  string
+	Label string
+}
+
+// TrainNaiveBayes fits a classifier to labeled examples
+func TrainNaiveBayes(examples []Example) *NaiveBayes {
+	nb := &NaiveBayes{
+		docs:       make(map[string]int),
+		words:      make(map[string]map[string]int),
+		totalWords: make(map[string]int),
+		vocab:      make(map[string]bool),
+	}
+	for _, ex := range examples {
+		if _, ok := nb.words[ex.Label]; !ok {
+			nb.words[ex.Label] = make(map[string]int)
+			nb.classes = append(nb.classes, ex.Label)
+		}
+		nb.docs[ex.Label]++
+		nb.total++
+		for _, w := range bayesTokens(ex.Text) {
+			nb.words[ex.Label][w]++
+			nb.totalWords[ex.Label]++
+			nb.vocab[w] = true
+		}
+	}
+	sort.Strings(nb.classes)
+	return nb
+}
+
+// Classes returns the labels the classifier was trained on
+func (nb *NaiveBayes) Classes() []string {
+	return nb.classes
+}
+
+// Predict returns the most probable label for a text and its posterior
+// probability. It returns "" when the classifier has no classes.
+func (nb *NaiveBayes) Predict(text string) (string, float64) {
+	posterior := nb.Posterior(text)
+	if len(posterior) == 0 {
+		return "", 0
+	}
+	return posterior[0].Name, posterior[0].Score
+}
+
+// Posterior returns the probability of each class for a text, most probable
+// first
+func (nb *NaiveBayes) Posterior(text string) []model.LabelScore {
+	if len(nb.classes) == 0 {
+		return nil
+	}
+	tokens := bayesTokens(text)
+	vocab := float64(len(nb.vocab))
+
+	logp := make([]float64, len(nb.classes))
+	best := 0
+	for i, class := range nb.classes {
+		lp := math.Log(float64(nb.docs[class]) / float64(nb.total))
+		denom := float64(nb.totalWords[class]) + vocab
+		for _, w := range tokens {
+			if !nb.vocab[w] {
+				continue
+			}
+			lp += math.Log((float64(nb.words[class][w]) + 1) / denom)
+		}
+		logp[i] = lp
+		if lp > logp[best] {
+			best = i
+		}
+	}
+
+	// Normalise in log space, relative to the best class, to avoid underflow
+	sum := 0.0
+	for _, lp := range logp {
+		sum += math.Exp(lp - logp[best])
+	}
+	posterior := make([]model.LabelScore, len(nb.classes))
+	for i, class := range nb.classes {
+		posterior[i] = model.LabelScore{Name: class, Score: math.Exp(logp[i]-logp[best]) / sum}
+	}
+	sort.SliceStable(posterior, func(i, j int) bool { return posterior[i].Score > posterior[j].Score })
+	return posterior
+}
+
+// Knows reports whether any of a text's words were seen in training. Without
+// one, the posterior is just the class priors.
+func (nb *NaiveBayes) Knows(text string) bool {
+	for _, w := range bayesTokens(text) {
+		if nb.vocab[w] {
+			return true
+		}
+	}
+	return false
+}
+
+// facet classifies a text as a facet whose confidence is the margin of the
+// most probable class over the next
+func (nb *NaiveBayes) facet(text string) facet {
+	f := facet{scores: nb.Posterior(text)}
+	switch len(f.scores) {
+	case 0:
+	case 1:
+		f.confidence = f.scores[0].Score
+	default:
+		f.confidence = f.scores[0].Score - f.scores[1].Score
+	}
+	return f
+}
+
+// bayesStopWords are too common to say anything about a beat's class
+var bayesStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "that": true,
+	"this": true, "from": true, "are": true, "was": true, "but": true,
+	"not": true, "you": true, "have": true, "has": true, "had": true,
+	"its": true, "it's": true, "into": true, "about": true, "just": true,
+}
+
+// bayesTokens splits text into lowercased words of two or more letters
+func bayesTokens(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+	tokens := fields[:0]
+	for _, f := range fields {
+		f = strings.Trim(f, "'")
+		if len(f) < 2 || bayesStopWords[f] {
+			continue
+		}
+		tokens = append(tokens, f)
+	}
+	return tokens
+}
+
+// minTrainingExamples is how many labeled beats a facet needs, across at
+// least two labels, before its learned classifier is used
+const minTrainingExamples = 5
+
+// minLearnedMargin is how far the learned label must lead the next one before
+// it replaces the keyword classification
+const minLearnedMargin = 0.3
+
+// LabeledBeat is a beat whose channel and/or source is known: set by hand,
+// or for sources, given by impetus.meta
+type LabeledBeat struct {
+	Beat    model.Beat
+	Channel model.Channel
+	Source  model.Source
+
+	SourceFromMeta bool // the keyword scorer reads the same meta, so this is no test of it
+}
+
+// LabeledBeats returns the beats with a known channel or source, sorted by ID
+func (d *Definition) LabeledBeats(beats []model.Beat, overrides map[string]model.TaxonomyOverride) []LabeledBeat {
+	var labeled []LabeledBeat
+	for _, beat := range beats {
+		lb := LabeledBeat{Beat: beat}
+		if o, ok := overrides[beat.ID]; ok {
+			if ch, err := d.ParseChannel(o.Channel); err == nil {
+				lb.Channel = ch
+			}
+			if src, err := d.ParseSource(o.Source); err == nil {
+				lb.Source = src
+			}
+		}
+		if lb.Source == model.SourceUnknown {
+			if value, ok := beat.Impetus.Meta["channel"]; ok {
+				if name, found := d.Meta[strings.ToLower(value)]; found {
+					lb.Source = model.Source(name)
+					lb.SourceFromMeta = true
+				}
+			}
+		}
+		if lb.Channel != model.ChannelUnknown || lb.Source != model.SourceUnknown {
+			labeled = append(labeled, lb)
+		}
+	}
+	sort.Slice(labeled, func(i, j int) bool { return labeled[i].Beat.ID < labeled[j].Beat.ID })
+	return labeled
+}
+
+// Classifier combines the definition's keyword scorer with naive Bayes
+// models learned from labeled beats. The learned label is used where the beat
+// shares words with the training beats and the model is clearly more
+// confident than the keywords.
+type Classifier struct {
+	def     *Definition
+	channel *NaiveBayes // nil until there are enough labeled beats
+	source  *NaiveBayes
+}
+
+// NewClassifier trains a classifier on the labeled beats among beats. With
+// learning turned off, or too few labels, it classifies by keywords alone.
+func (d *Definition) NewClassifier(labeled []LabeledBeat) *Classifier {
+	c := &Classifier{def: d}
+	if !d.Learn {
+		return c
+	}
+
+	var channels, sources []Example
+	for _, lb := range labeled {
+		text := beatText(lb.Beat)
+		if lb.Channel != model.ChannelUnknown {
+			channels = append(channels, Example{Text: text, Label: string(lb.Channel)})
+		}
+		if lb.Source != model.SourceUnknown {
+			sources = append(sources, Example{Text: text, Label: string(lb.Source)})
+		}
+	}
+	c.channel = trainIfEnough(channels)
+	c.source = trainIfEnough(sources)
+	return c
+}
+
+func trainIfEnough(examples []Example) *NaiveBayes {
+	if len(examples) < minTrainingExamples {
+		return nil
+	}
+	nb := TrainNaiveBayes(examples)
+	if len(nb.Classes()) < 2 {
+		return nil
+	}
+	return nb
+}
+
+func beatText(beat model.Beat) string {
+	return beat.Impetus.Label + " " + beat.Content
+}
+
+// Learned reports whether the classifier has learned channels and sources
+func (c *Classifier) Learned() (channel, source bool) {
+	return c.channel != nil, c.source != nil
+}
+
+// Classify classifies a beat by keywords, replacing the channel or source
+// scores with the learned ones where those are more confident
+func (c *Classifier) Classify(beat model.Beat) model.Taxonomy {
+	channel, source := c.def.scoreFacets(beat)
+	text := beatText(beat)
+
+	channel = learnedOver(c.channel, text, channel)
+	source = learnedOver(c.source, text, source)
+	return c.def.taxonomy(channel, source)
+}
+
+// learnedOver returns nb's facet for text when it overrides the keyword
+// result, or keyword otherwise. A text with no known words would get the
+// majority class from the priors alone, so it keeps the keyword result.
+func learnedOver(nb *NaiveBayes, text string, keyword facet) facet {
+	if nb == nil || !nb.Knows(text) {
+		return keyword
+	}
+	learned := nb.facet(text)
+	if learned.confidence < minLearnedMargin || learned.confidence <= keyword.confidence {
+		return keyword
+	}
+	return learned
+}
@@ -1,116 +1,154 @@
 package taxonomy
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/bierlingm/beats_viewer/pkg/model"
 )
 
-// Classify auto-classifies a beat by Channel and Source
-func Classify(beat model.Beat) model.Taxonomy {
-	label := strings.ToLower(beat.Impetus.Label)
-	content := strings.ToLower(beat.Content)
+// classifierVersion changes whenever classification behaves differently, so
+// caches classified by an older btv are reclassified
+const classifierVersion = 4
 
-	channel := detectChannel(label, content)
-	source := detectSource(label, beat.Impetus.Meta)
-	confidence := calculateConfidence(label, content, channel, source)
+// evidencePrior damps the confidence of a facet with little evidence: a
+// lone content match is a weak signal even with no runner-up
+const evidencePrior = 2.0
 
-	return model.Taxonomy{
-		Channel:    channel,
-		Source:     source,
-		Confidence: confidence,
-	}
+// facet is the classification of a beat's channel or source: every label
+// that scored, ranked, and how sure the classifier is of the first
+type facet struct {
+	scores     []model.LabelScore
+	confidence float64
 }
 
-func detectChannel(label, content string) model.Channel {
-	scores := make(map[model.Channel]int)
-
-	for ch, patterns := range ChannelPatterns {
-		for _, pattern := range patterns {
-			if strings.Contains(label, pattern) {
-				scores[ch] += 3
-			}
-			if strings.Contains(content, pattern) {
-				scores[ch] += 1
-			}
-		}
+// label returns the best label, or "" when nothing scored
+func (f facet) label() string {
+	if len(f.scores) == 0 {
+		return ""
 	}
+	return f.scores[0].Name
+}
 
-	best := model.ChannelUnknown
-	bestScore := 0
-	for ch, score := range scores {
-		if score > bestScore {
-			best = ch
-			bestScore = score
+// secondary returns the runners-up scoring at least threshold
+func (f facet) secondary(threshold float64) []string {
+	var names []string
+	for i := 1; i < len(f.scores); i++ {
+		if f.scores[i].Score >= threshold {
+			names = append(names, f.scores[i].Name)
 		}
 	}
+	return names
+}
 
-	if bestScore == 0 {
-		return model.ChannelDiscovery
-	}
+// Classify auto-classifies a beat by Channel and Source
+func (d *Definition) Classify(beat model.Beat) model.Taxonomy {
+	channel, source := d.scoreFacets(beat)
+	return d.taxonomy(channel, source)
+}
 
-	return best
+func (d *Definition) scoreFacets(beat model.Beat) (channel, source facet) {
+	label := strings.ToLower(beat.Impetus.Label)
+	content := strings.ToLower(beat.Content)
+	return d.scoreChannels(label, content), d.scoreSources(label, beat.Impetus.Meta)
 }
 
-func detectSource(label string, meta model.ImpetusMeta) model.Source {
-	if ch, ok := meta["channel"]; ok {
-		if source, found := MetaChannelMap[strings.ToLower(ch)]; found {
-			return source
-		}
+// taxonomy builds a beat's taxonomy from its scored facets. A facet where
+// nothing scored falls back to the default with no confidence.
+func (d *Definition) taxonomy(channel, source facet) model.Taxonomy {
+	t := model.Taxonomy{
+		Channel:           model.Channel(d.DefaultChannel),
+		Source:            model.Source(d.DefaultSource),
+		Confidence:        (channel.confidence + source.confidence) / 2,
+		ChannelConfidence: channel.confidence,
+		SourceConfidence:  source.confidence,
+		Channels:          channel.scores,
+		Sources:           source.scores,
 	}
+	if name := channel.label(); name != "" {
+		t.Channel = model.Channel(name)
+	}
+	if name := source.label(); name != "" {
+		t.Source = model.Source(name)
+	}
+	for _, name := range channel.secondary(d.LabelThreshold) {
+		t.SecondaryChannels = append(t.SecondaryChannels, model.Channel(name))
+	}
+	for _, name := range source.secondary(d.LabelThreshold) {
+		t.SecondarySources = append(t.SecondarySources, model.Source(name))
+	}
+	return t
+}
 
-	scores := make(map[model.Source]int)
-	for src, patterns := range SourcePatterns {
-		for _, pattern := range patterns {
+// scoreChannels scores each channel's patterns against the label and content
+func (d *Definition) scoreChannels(label, content string) facet {
+	raw := make([]float64, len(d.Channels))
+	for i, ch := range d.Channels {
+		for _, pattern := range ch.Patterns {
 			if strings.Contains(label, pattern) {
-				scores[src] += 2
+				raw[i] += 3
+			}
+			if strings.Contains(content, pattern) {
+				raw[i] += 1
 			}
 		}
 	}
+	return rankScores(d.Channels, raw)
+}
 
-	best := model.SourceUnknown
-	bestScore := 0
-	for src, score := range scores {
-		if score > bestScore {
-			best = src
-			bestScore = score
+// scoreSources scores each source's patterns against the label. A source
+// named by impetus meta is certain.
+func (d *Definition) scoreSources(label string, meta model.ImpetusMeta) facet {
+	if ch, ok := meta["channel"]; ok {
+		if source, found := d.Meta[strings.ToLower(ch)]; found {
+			return facet{scores: []model.LabelScore{{Name: source, Score: 1}}, confidence: 1}
 		}
 	}
 
-	if bestScore == 0 {
-		return model.SourceInternal
+	raw := make([]float64, len(d.Sources))
+	for i, src := range d.Sources {
+		for _, pattern := range src.Patterns {
+			if strings.Contains(label, pattern) {
+				raw[i] += 2
+			}
+		}
 	}
-
-	return best
+	return rankScores(d.Sources, raw)
 }
 
-func calculateConfidence(label, content string, ch model.Channel, src model.Source) float64 {
-	confidence := 0.3
-
-	if label != "" && label != "manual entry" {
-		confidence += 0.3
+// rankScores turns raw pattern scores into shares of their total, best
+// first with ties in definition order. The confidence is the best score's
+// margin over the runner-up, relative to the evidence behind it.
+func rankScores(categories []Category, raw []float64) facet {
+	var f facet
+	total := 0.0
+	for i, c := range categories {
+		if raw[i] > 0 {
+			f.scores = append(f.scores, model.LabelScore{Name: c.Name, Score: raw[i]})
+			total += raw[i]
+		}
 	}
-
-	if ch != model.ChannelUnknown {
-		confidence += 0.2
+	if total == 0 {
+		return f
 	}
+	sort.SliceStable(f.scores, func(i, j int) bool { return f.scores[i].Score > f.scores[j].Score })
 
-	if src != model.SourceUnknown {
-		confidence += 0.2
+	top, second := f.scores[0].Score, 0.0
+	if len(f.scores) > 1 {
+		second = f.scores[1].Score
 	}
-
-	if confidence > 1.0 {
-		confidence = 1.0
+	f.confidence = (top - second) / (top + evidencePrior)
+	for i := range f.scores {
+		f.scores[i].Score /= total
 	}
-
-	return confidence
+	return f
 }
 
 // ClassifyAll classifies all beats and returns a map of beat ID to taxonomy
-func ClassifyAll(beats []model.Beat) map[string]model.Taxonomy {
+func (d *Definition) ClassifyAll(beats []model.Beat) map[string]model.Taxonomy {
 	result := make(map[string]model.Taxonomy)
 	for _, beat := range beats {
-		result[beat.ID] = Classify(beat)
+		result[beat.ID] = d.Classify(beat)
 	}
 	return result
 }
@@ -0,0 +1,238 @@
+package taxonomy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bierlingm/beats_viewer/pkg/config"
+	"github.com/bierlingm/beats_viewer/pkg/model"
+)
+
+// ConfigSection is the config file section holding taxonomy definitions
+const ConfigSection = "taxonomy"
+
+// Category is a channel or source: its name, the color it is drawn in and
+// the words that suggest it
+type Category struct {
+	Name     string   `json:"name"`
+	Color    string   `json:"color,omitempty"`
+	Patterns []string `json:"patterns,omitempty"`
+}
+
+// DefinitionConfig is the user-editable form of a taxonomy. A category named
+// like an existing one adds its patterns to it and may change its color;
+// other names are added after the existing categories.
+type DefinitionConfig struct {
+	Channels       []Category        `json:"channels,omitempty"`
+	Sources        []Category        `json:"sources,omitempty"`
+	Meta           map[string]string `json:"meta,omitempty"`            // impetus meta "channel" value -> source name
+	DefaultChannel string            `json:"default_channel,omitempty"` // when no channel pattern matches
+	DefaultSource  string            `json:"default_source,omitempty"`  // when no source pattern matches
+	Learn          *bool             `json:"learn,omitempty"`           // learn from labeled beats (default true)
+	LabelThreshold float64           `json:"label_threshold,omitempty"` // share of scores a secondary label needs
+}
+
+// Definition is the merged set of channels and sources beats are classified
+// into, in display order
+type Definition struct {
+	Channels       []Category        `json:"channels"`
+	Sources        []Category        `json:"sources"`
+	Meta           map[string]string `json:"meta"` // lowercased meta value -> source name
+	DefaultChannel string            `json:"default_channel"`
+	DefaultSource  string            `json:"default_source"`
+	Learn          bool              `json:"learn"`
+	LabelThreshold float64           `json:"label_threshold"`
+}
+
+// DefaultLabelThreshold is the share of a beat's channel or source scores a
+// runner-up needs to become a secondary label
+const DefaultLabelThreshold = 0.25
+
+// BuiltinDefinition returns the taxonomy compiled into btv
+func BuiltinDefinition() *Definition {
+	d := &Definition{
+		Meta:           make(map[string]string, len(MetaChannelMap)),
+		DefaultChannel: model.ChannelDiscovery.String(),
+		DefaultSource:  model.SourceInternal.String(),
+		Learn:          true,
+		LabelThreshold: DefaultLabelThreshold,
+	}
+	for _, ch := range model.AllChannels() {
+		d.Channels = append(d.Channels, Category{
+			Name:     ch.String(),
+			Color:    ChannelColors[ch],
+			Patterns: append([]string(nil), ChannelPatterns[ch]...),
+		})
+	}
+	for _, src := range model.AllSources() {
+		d.Sources = append(d.Sources, Category{
+			Name:     src.String(),
+			Patterns: append([]string(nil), SourcePatterns[src]...),
+		})
+	}
+	for value, src := range MetaChannelMap {
+		d.Meta[value] = src.String()
+	}
+	return d
+}
+
+// Merge adds a config's channels, sources and meta mappings on top of the
+// definition
+func (d *Definition) Merge(cfg DefinitionConfig) error {
+	var err error
+	if d.Channels, err = mergeCategories(d.Channels, cfg.Channels); err != nil {
+		return fmt.Errorf("channels: %w", err)
+	}
+	if d.Sources, err = mergeCategories(d.Sources, cfg.Sources); err != nil {
+		return fmt.Errorf("sources: %w", err)
+	}
+	for value, source := range cfg.Meta {
+		d.Meta[strings.ToLower(value)] = source
+	}
+	if cfg.DefaultChannel != "" {
+		d.DefaultChannel = cfg.DefaultChannel
+	}
+	if cfg.DefaultSource != "" {
+		d.DefaultSource = cfg.DefaultSource
+	}
+	if cfg.Learn != nil {
+		d.Learn = *cfg.Learn
+	}
+	if cfg.LabelThreshold != 0 {
+		d.LabelThreshold = cfg.LabelThreshold
+	}
+	return nil
+}
+
+func mergeCategories(existing, added []Category) ([]Category, error) {
+	for _, c := range added {
+		if strings.TrimSpace(c.Name) == "" {
+			return nil, fmt.Errorf("category name must not be empty")
+		}
+		i := findCategory(existing, c.Name)
+		if i < 0 {
+			existing = append(existing, Category{Name: c.Name})
+			i = len(existing) - 1
+		}
+		if c.Color != "" {
+			existing[i].Color = c.Color
+		}
+		for _, p := range c.Patterns {
+			existing[i].Patterns = append(existing[i].Patterns, strings.ToLower(p))
+		}
+	}
+	return existing, nil
+}
+
+func findCategory(categories []Category, name string) int {
+	for i, c := range categories {
+		if strings.EqualFold(c.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Validate checks that meta mappings and defaults name known categories and
+// that the label threshold is a share
+func (d *Definition) Validate() error {
+	for value, source := range d.Meta {
+		if _, err := d.ParseSource(source); err != nil {
+			return fmt.Errorf("meta %q: %w", value, err)
+		}
+	}
+	if _, err := d.ParseChannel(d.DefaultChannel); err != nil {
+		return fmt.Errorf("default_channel: %w", err)
+	}
+	if _, err := d.ParseSource(d.DefaultSource); err != nil {
+		return fmt.Errorf("default_source: %w", err)
+	}
+	if d.LabelThreshold <= 0 || d.LabelThreshold > 1 {
+		return fmt.Errorf("label_threshold %v: must be above 0 and at most 1", d.LabelThreshold)
+	}
+	return nil
+}
+
+// ChannelList returns the defined channels in display order
+func (d *Definition) ChannelList() []model.Channel {
+	channels := make([]model.Channel, len(d.Channels))
+	for i, c := range d.Channels {
+		channels[i] = model.Channel(c.Name)
+	}
+	return channels
+}
+
+// SourceList returns the defined sources in display order
+func (d *Definition) SourceList() []model.Source {
+	sources := make([]model.Source, len(d.Sources))
+	for i, c := range d.Sources {
+		sources[i] = model.Source(c.Name)
+	}
+	return sources
+}
+
+// ParseChannel returns the defined channel with the given name, in any case
+func (d *Definition) ParseChannel(name string) (model.Channel, error) {
+	if i := findCategory(d.Channels, name); i >= 0 {
+		return model.Channel(d.Channels[i].Name), nil
+	}
+	return model.ChannelUnknown, fmt.Errorf("unknown channel %q", name)
+}
+
+// ParseSource returns the defined source with the given name, in any case
+func (d *Definition) ParseSource(name string) (model.Source, error) {
+	if i := findCategory(d.Sources, name); i >= 0 {
+		return model.Source(d.Sources[i].Name), nil
+	}
+	return model.SourceUnknown, fmt.Errorf("unknown source %q", name)
+}
+
+// ChannelColors returns the color of each channel that has one
+func (d *Definition) ChannelColors() map[model.Channel]string {
+	colors := make(map[model.Channel]string)
+	for _, c := range d.Channels {
+		if c.Color != "" {
+			colors[model.Channel(c.Name)] = c.Color
+		}
+	}
+	return colors
+}
+
+// Fingerprint identifies the definition's contents so beats can be
+// reclassified when it changes
+func (d *Definition) Fingerprint() string {
+	data, _ := json.Marshal(struct {
+		Version    int
+		Definition *Definition
+	}{classifierVersion, d})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// LoadDefinition returns the effective taxonomy for a project: the built-in
+// channels and sources plus those of the global and project config files,
+// which are combined rather than overlaid. It also returns the files that
+// contributed.
+func LoadDefinition(beatsDir string) (*Definition, []string, error) {
+	d := BuiltinDefinition()
+	sources, err := config.EachSection(beatsDir, ConfigSection, func(path string, raw json.RawMessage) error {
+		var cfg DefinitionConfig
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			return fmt.Errorf("decoding %s in %s: %w", ConfigSection, path, err)
+		}
+		if err := d.Merge(cfg); err != nil {
+			return fmt.Errorf("%s in %s: %w", ConfigSection, path, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return BuiltinDefinition(), sources, err
+	}
+	if err := d.Validate(); err != nil {
+		return BuiltinDefinition(), sources, err
+	}
+	return d, sources, nil
+}
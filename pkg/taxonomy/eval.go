@@ -0,0 +1,111 @@
+package taxonomy
+
+import "github.com/bierlingm/beats_viewer/pkg/model"
+
+// FacetEval is how well channels or sources of held-out labeled beats were
+// predicted
+type FacetEval struct {
+	Examples   int                       `json:"examples"`
+	Keyword    float64                   `json:"keyword_accuracy"`    // keyword scorer alone
+	Bayes      float64                   `json:"bayes_accuracy"`      // learned model alone; 0 when it could not be trained
+	Classifier float64                   `json:"classifier_accuracy"` // the two combined, as used for the cache
+	Confusion  map[string]map[string]int `json:"confusion"`           // actual -> predicted by the classifier -> count
+}
+
+// Evaluation reports cross-validated accuracy over the labeled beats
+type Evaluation struct {
+	Labeled int       `json:"labeled"`
+	Folds   int       `json:"folds"`
+	Channel FacetEval `json:"channel"`
+	Source  FacetEval `json:"source"`
+}
+
+// Evaluate cross-validates the classifier: each labeled beat is predicted by
+// a classifier trained on the other folds. Beats are assigned to folds by
+// their position in ID order, so results are repeatable. Sources taken from
+// impetus meta are trained on but not scored, since the keyword scorer reads
+// the same meta; only labels set by hand count as ground truth.
+func (d *Definition) Evaluate(labeled []LabeledBeat, folds int) Evaluation {
+	if folds > len(labeled) {
+		folds = len(labeled)
+	}
+	if folds < 2 {
+		folds = 2
+	}
+	eval := Evaluation{
+		Labeled: len(labeled),
+		Folds:   folds,
+		Channel: FacetEval{Confusion: make(map[string]map[string]int)},
+		Source:  FacetEval{Confusion: make(map[string]map[string]int)},
+	}
+
+	var channelHits, sourceHits [3]int // keyword, bayes, classifier
+	for f := 0; f < folds; f++ {
+		var train, test []LabeledBeat
+		for i, lb := range labeled {
+			if i%folds == f {
+				test = append(test, lb)
+			} else {
+				train = append(train, lb)
+			}
+		}
+
+		// Train regardless of the learn setting, which is what is being judged
+		learning := *d
+		learning.Learn = true
+		classifier := learning.NewClassifier(train)
+
+		for _, lb := range test {
+			keyword := d.Classify(lb.Beat)
+			combined := classifier.Classify(lb.Beat)
+			text := beatText(lb.Beat)
+
+			if lb.Channel != model.ChannelUnknown {
+				bayes := ""
+				if classifier.channel != nil {
+					bayes, _ = classifier.channel.Predict(text)
+				}
+				tally(&eval.Channel, &channelHits, string(lb.Channel),
+					string(keyword.Channel), bayes, string(combined.Channel))
+			}
+			if lb.Source != model.SourceUnknown && !lb.SourceFromMeta {
+				bayes := ""
+				if classifier.source != nil {
+					bayes, _ = classifier.source.Predict(text)
+				}
+				tally(&eval.Source, &sourceHits, string(lb.Source),
+					string(keyword.Source), bayes, string(combined.Source))
+			}
+		}
+	}
+
+	finish(&eval.Channel, channelHits)
+	finish(&eval.Source, sourceHits)
+	return eval
+}
+
+func tally(e *FacetEval, hits *[3]int, actual, keyword, bayes, combined string) {
+	e.Examples++
+	for i, predicted := range []string{keyword, bayes, combined} {
+		if predicted == actual {
+			hits[i]++
+		}
+	}
+	if e.Confusion[actual] == nil {
+		e.Confusion[actual] = make(map[string]int)
+	}
+	if combined == "" {
+		combined = "Unknown"
+	}
+	e.Confusion[actual][combined]++
+}
+
+func finish(e *FacetEval, hits [3]int) {
+	if e.Examples == 0 {
+		return
+	}
+	n := float64(e.Examples)
+	e.Keyword = float64(hits[0]) / n
+	e.Bayes = float64(hits[1]) / n
+	e.Classifier = float64(hits[2]) / n
+}
@@ -0,0 +1,50 @@
+package taxonomy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/bierlingm/beats_viewer/pkg/model"
+)
+
+// ApplyOverride replaces the automatic channel and/or source with the ones
+// set by hand, each with full confidence and no secondary labels. The result
+// is marked manual only when both are set by hand; otherwise the other facet
+// keeps its automatic confidence. Names the definition doesn't know keep the
+// automatic value.
+func (d *Definition) ApplyOverride(auto model.Taxonomy, o model.TaxonomyOverride) model.Taxonomy {
+	t := auto
+	ch, chErr := d.ParseChannel(o.Channel)
+	src, srcErr := d.ParseSource(o.Source)
+	if chErr == nil {
+		t.Channel = ch
+		t.Channels = []model.LabelScore{{Name: string(ch), Score: 1}}
+		t.SecondaryChannels = nil
+		t.ChannelConfidence = 1.0
+	}
+	if srcErr == nil {
+		t.Source = src
+		t.Sources = []model.LabelScore{{Name: string(src), Score: 1}}
+		t.SecondarySources = nil
+		t.SourceConfidence = 1.0
+	}
+	if chErr != nil && srcErr != nil {
+		return auto
+	}
+	t.Confidence = (t.ChannelConfidence + t.SourceConfidence) / 2
+	t.Manual = chErr == nil && srcErr == nil
+	return t
+}
+
+// OverridesFingerprint identifies a set of overrides, so the cache can tell
+// when they were edited
+func OverridesFingerprint(overrides map[string]model.TaxonomyOverride) string {
+	if len(overrides) == 0 {
+		return ""
+	}
+	// Maps marshal with sorted keys, so equal sets give equal fingerprints
+	data, _ := json.Marshal(overrides)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
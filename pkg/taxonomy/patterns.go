@@ -24,6 +24,17 @@ var SourcePatterns = map[model.Source][]string{
 	model.SourceInternal:     {"thinking", "reflection", "realized", "insight", "idea"},
 }
 
+// ChannelColors maps channels to the colors they are drawn in
+var ChannelColors = map[model.Channel]string{
+	model.ChannelCoaching:    "#FF6B6B",
+	model.ChannelResearch:    "#4ECDC4",
+	model.ChannelDiscovery:   "#45B7D1",
+	model.ChannelDevelopment: "#96CEB4",
+	model.ChannelReflection:  "#FFEAA7",
+	model.ChannelReference:   "#DDA0DD",
+	model.ChannelMilestone:   "#F39C12",
+}
+
 // MetaChannelMap maps meta["channel"] values to Source
 var MetaChannelMap = map[string]model.Source{
 	"twitter":     model.SourceTwitter,
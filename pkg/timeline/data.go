@@ -2,6 +2,7 @@ package timeline
 
 import (
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/bierlingm/beats_viewer/pkg/model"
@@ -31,6 +32,21 @@ func (z ZoomLevel) String() string {
 	}
 }
 
+// ParseZoomLevel parses a zoom level name such as "week"
+func ParseZoomLevel(s string) (ZoomLevel, bool) {
+	switch strings.ToLower(s) {
+	case "day":
+		return ZoomDay, true
+	case "week":
+		return ZoomWeek, true
+	case "month":
+		return ZoomMonth, true
+	case "quarter":
+		return ZoomQuarter, true
+	}
+	return ZoomMonth, false
+}
+
 type TimelineBucket struct {
 	Date      time.Time
 	BeatCount int
@@ -130,6 +146,16 @@ func truncateToZoom(t time.Time, zoom ZoomLevel) time.Time {
 	return t
 }
 
+// PeriodsBefore returns the start of the period n periods before the one
+// containing t
+func PeriodsBefore(t time.Time, zoom ZoomLevel, n int) time.Time {
+	start := truncateToZoom(t, zoom)
+	for i := 0; i < n; i++ {
+		start = truncateToZoom(start.AddDate(0, 0, -1), zoom)
+	}
+	return start
+}
+
 func (td *TimelineData) MaxBeatCount() int {
 	max := 0
 	for _, b := range td.Buckets {
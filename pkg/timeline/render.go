@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/bierlingm/beats_viewer/pkg/model"
+	"github.com/bierlingm/beats_viewer/pkg/taxonomy"
 
 	"github.com/charmbracelet/lipgloss"
 )
@@ -24,16 +25,6 @@ var (
 	timelineSelectedStyle = lipgloss.NewStyle().
 				Bold(true).
 				Background(lipgloss.Color("#383838"))
-
-	channelColors = map[model.Channel]lipgloss.Color{
-		model.ChannelCoaching:    lipgloss.Color("#FF6B6B"),
-		model.ChannelResearch:    lipgloss.Color("#4ECDC4"),
-		model.ChannelDiscovery:   lipgloss.Color("#45B7D1"),
-		model.ChannelDevelopment: lipgloss.Color("#96CEB4"),
-		model.ChannelReflection:  lipgloss.Color("#FFEAA7"),
-		model.ChannelReference:   lipgloss.Color("#DDA0DD"),
-		model.ChannelMilestone:   lipgloss.Color("#F39C12"),
-	}
 )
 
 type TimelineRenderer struct {
@@ -43,16 +34,23 @@ type TimelineRenderer struct {
 	cursorPos     int
 	scrollOffset  int
 	showColors    bool
+	channelColors map[model.Channel]string
 }
 
 func NewTimelineRenderer(width, height int) *TimelineRenderer {
 	return &TimelineRenderer{
-		width:      width,
-		height:     height,
-		showColors: false,
+		width:         width,
+		height:        height,
+		showColors:    false,
+		channelColors: taxonomy.ChannelColors,
 	}
 }
 
+// SetChannelColors sets the colors buckets are drawn in by dominant channel
+func (tr *TimelineRenderer) SetChannelColors(colors map[model.Channel]string) {
+	tr.channelColors = colors
+}
+
 func (tr *TimelineRenderer) SetData(data *TimelineData) {
 	tr.data = data
 	tr.cursorPos = 0
@@ -167,7 +165,7 @@ func (tr *TimelineRenderer) renderDots(buckets []TimelineBucket, maxCount int) s
 
 		var color lipgloss.Color
 		if tr.showColors && len(bucket.ByChannel) > 0 {
-			color = dominantChannelColor(bucket.ByChannel)
+			color = tr.dominantChannelColor(bucket.ByChannel)
 		} else {
 			color = lipgloss.Color("#73F59F")
 		}
@@ -212,7 +210,7 @@ func densityChar(intensity float64) string {
 	}
 }
 
-func dominantChannelColor(byChannel map[model.Channel]int) lipgloss.Color {
+func (tr *TimelineRenderer) dominantChannelColor(byChannel map[model.Channel]int) lipgloss.Color {
 	var maxChannel model.Channel
 	maxCount := 0
 
@@ -223,8 +221,8 @@ func dominantChannelColor(byChannel map[model.Channel]int) lipgloss.Color {
 		}
 	}
 
-	if color, ok := channelColors[maxChannel]; ok {
-		return color
+	if color, ok := tr.channelColors[maxChannel]; ok {
+		return lipgloss.Color(color)
 	}
 	return lipgloss.Color("#73F59F")
 }
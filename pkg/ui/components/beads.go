@@ -0,0 +1,60 @@
+package components
+
+import "github.com/bierlingm/beats_viewer/pkg/model"
+
+// BeadFilter narrows beats by the state of the beads made from them
+type BeadFilter int
+
+const (
+	BeadFilterAll    BeadFilter = iota
+	BeadFilterOpen              // beats with a bead still open
+	BeadFilterClosed            // beats whose beads are all closed
+)
+
+// Next cycles all → has open bead → bead closed → all
+func (f BeadFilter) Next() BeadFilter {
+	return (f + 1) % 3
+}
+
+func (f BeadFilter) String() string {
+	switch f {
+	case BeadFilterOpen:
+		return "has open bead"
+	case BeadFilterClosed:
+		return "bead closed"
+	default:
+		return "all beats"
+	}
+}
+
+// FilterByBeads keeps the beats matching a bead filter, judging beads by the
+// statuses known. A bead with no known status counts as neither open nor
+// closed.
+func FilterByBeads(beats []model.EnrichedBeat, filter BeadFilter, statuses map[string]model.BeadStatus) []model.EnrichedBeat {
+	if filter == BeadFilterAll {
+		return beats
+	}
+
+	var filtered []model.EnrichedBeat
+	for _, eb := range beats {
+		if len(eb.LinkedBeads) == 0 {
+			continue
+		}
+		open, closed := 0, 0
+		for _, id := range eb.LinkedBeads {
+			status, ok := statuses[id]
+			switch {
+			case !ok:
+			case status.Closed():
+				closed++
+			default:
+				open++
+			}
+		}
+		if filter == BeadFilterOpen && open > 0 ||
+			filter == BeadFilterClosed && closed == len(eb.LinkedBeads) {
+			filtered = append(filtered, eb)
+		}
+	}
+	return filtered
+}
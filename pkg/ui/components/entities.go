@@ -5,6 +5,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/bierlingm/beats_viewer/pkg/entity"
 	"github.com/bierlingm/beats_viewer/pkg/model"
 
 	"github.com/charmbracelet/lipgloss"
@@ -25,36 +26,51 @@ var (
 )
 
 type EntityItem struct {
+	ID      string // see model.EntityID
 	Name    string
 	Type    model.EntityType
 	Count   int
 	BeatIDs []string
+	NPMI    float64 // association with the selection, for related entities
+}
+
+// MaxRelatedEntities caps the related entities listed for a selection
+const MaxRelatedEntities = 8
+
+type entitySection struct {
+	title    string
+	typ      model.EntityType
+	items    []EntityItem
+	expanded bool
 }
 
 type EntitySidebar struct {
 	width  int
 	height int
 
-	people   []EntityItem
-	tools    []EntityItem
-	concepts []EntityItem
+	sections []entitySection
+	related  *entitySection // entities co-occurring with the selection
+	graph    *entity.Graph
 
-	selectedEntity *string
+	selectedEntity *string // entity ID
+	selectedName   string
+	markedEntity   *string
 	cursorPos      int
 	scrollOffset   int
-
-	expandPeople   bool
-	expandTools    bool
-	expandConcepts bool
+	focused        bool
 }
 
 func NewEntitySidebar(width, height int) *EntitySidebar {
 	return &EntitySidebar{
-		width:          width,
-		height:         height,
-		expandPeople:   true,
-		expandTools:    true,
-		expandConcepts: true,
+		width:  width,
+		height: height,
+		sections: []entitySection{
+			{title: "People", typ: model.EntityPerson, expanded: true},
+			{title: "Tools", typ: model.EntityTool, expanded: true},
+			{title: "Concepts", typ: model.EntityConcept, expanded: true},
+			{title: "Projects", typ: model.EntityProject, expanded: true},
+			{title: "Organizations", typ: model.EntityOrganization, expanded: true},
+		},
 	}
 }
 
@@ -63,16 +79,21 @@ func (e *EntitySidebar) SetSize(width, height int) {
 	e.height = height
 }
 
+// SetFocused sets whether the sidebar has keyboard focus
+func (e *EntitySidebar) SetFocused(focused bool) {
+	e.focused = focused
+}
+
 func (e *EntitySidebar) UpdateEntities(entities []model.Entity) {
 	entityMap := make(map[string]*EntityItem)
 
 	for _, ent := range entities {
-		key := fmt.Sprintf("%s-%d", ent.Name, ent.Type)
-		if existing, ok := entityMap[key]; ok {
+		if existing, ok := entityMap[ent.ID]; ok {
 			existing.Count = len(ent.BeatIDs)
 			existing.BeatIDs = ent.BeatIDs
 		} else {
-			entityMap[key] = &EntityItem{
+			entityMap[ent.ID] = &EntityItem{
+				ID:      ent.ID,
 				Name:    ent.Name,
 				Type:    ent.Type,
 				Count:   len(ent.BeatIDs),
@@ -81,38 +102,93 @@ func (e *EntitySidebar) UpdateEntities(entities []model.Entity) {
 		}
 	}
 
-	e.people = nil
-	e.tools = nil
-	e.concepts = nil
-
+	e.graph = entity.BuildGraph(entities, 1)
+	for i := range e.sections {
+		e.sections[i].items = nil
+	}
 	for _, item := range entityMap {
-		switch item.Type {
-		case model.EntityPerson:
-			e.people = append(e.people, *item)
-		case model.EntityTool:
-			e.tools = append(e.tools, *item)
-		case model.EntityConcept:
-			e.concepts = append(e.concepts, *item)
+		for i := range e.sections {
+			if e.sections[i].typ == item.Type {
+				e.sections[i].items = append(e.sections[i].items, *item)
+			}
 		}
 	}
 
-	sortByCount := func(items []EntityItem) {
-		sort.Slice(items, func(i, j int) bool {
-			return items[i].Count > items[j].Count
+	for i := range e.sections {
+		items := e.sections[i].items
+		sort.Slice(items, func(a, b int) bool {
+			if items[a].Count != items[b].Count {
+				return items[a].Count > items[b].Count
+			}
+			return items[a].Name < items[b].Name
+		})
+	}
+
+	e.updateRelated()
+	if last := e.totalItems() - 1; e.cursorPos > last {
+		e.cursorPos = last
+	}
+}
+
+// updateRelated rebuilds the related section for the selected entity
+func (e *EntitySidebar) updateRelated() {
+	if e.selectedEntity == nil || e.graph == nil {
+		e.related = nil
+		return
+	}
+
+	expanded := true
+	if e.related != nil {
+		expanded = e.related.expanded
+	}
+	section := &entitySection{
+		title:    "Related to " + e.selectedName,
+		expanded: expanded,
+	}
+	for _, edge := range e.graph.Related(*e.selectedEntity, MaxRelatedEntities, false) {
+		node, _ := e.graph.Node(edge.TargetID)
+		section.items = append(section.items, EntityItem{
+			ID:    edge.TargetID,
+			Name:  edge.Target,
+			Type:  node.Type,
+			Count: edge.Shared,
+			NPMI:  edge.NPMI,
 		})
 	}
+	e.related = section
+}
 
-	sortByCount(e.people)
-	sortByCount(e.tools)
-	sortByCount(e.concepts)
+// allSections returns the related section, if any, followed by the type sections
+func (e *EntitySidebar) allSections() []*entitySection {
+	var sections []*entitySection
+	if e.related != nil {
+		sections = append(sections, e.related)
+	}
+	for i := range e.sections {
+		sections = append(sections, &e.sections[i])
+	}
+	return sections
 }
 
+// SelectedEntity returns the ID of the entity filtered on, or nil
 func (e *EntitySidebar) SelectedEntity() *string {
 	return e.selectedEntity
 }
 
 func (e *EntitySidebar) ClearSelection() {
 	e.selectedEntity = nil
+	e.selectedName = ""
+	e.updateRelated()
+}
+
+// MarkedEntity returns the entity marked as the source of a merge
+func (e *EntitySidebar) MarkedEntity() *string {
+	return e.markedEntity
+}
+
+// SetMarked marks an entity to merge into the next one chosen, or clears the mark
+func (e *EntitySidebar) SetMarked(name *string) {
+	e.markedEntity = name
 }
 
 func (e *EntitySidebar) CursorUp() {
@@ -131,15 +207,12 @@ func (e *EntitySidebar) CursorDown() {
 }
 
 func (e *EntitySidebar) totalItems() int {
-	count := 3 // section headers
-	if e.expandPeople {
-		count += len(e.people)
-	}
-	if e.expandTools {
-		count += len(e.tools)
-	}
-	if e.expandConcepts {
-		count += len(e.concepts)
+	sections := e.allSections()
+	count := len(sections) // section headers
+	for _, section := range sections {
+		if section.expanded {
+			count += len(section.items)
+		}
 	}
 	return count
 }
@@ -154,93 +227,77 @@ func (e *EntitySidebar) ensureVisible() {
 }
 
 func (e *EntitySidebar) ToggleSelection() {
-	item := e.itemAtCursor()
+	item := e.ItemAtCursor()
 	if item == nil {
 		return
 	}
 
-	if e.selectedEntity != nil && *e.selectedEntity == item.Name {
+	before := e.relatedRows()
+	inRelated := e.cursorPos < before
+	if e.selectedEntity != nil && *e.selectedEntity == item.ID {
 		e.selectedEntity = nil
+		e.selectedName = ""
 	} else {
-		e.selectedEntity = &item.Name
+		id := item.ID
+		e.selectedEntity = &id
+		e.selectedName = item.Name
 	}
-}
+	e.updateRelated()
 
-func (e *EntitySidebar) ToggleSection() {
-	pos := 0
-
-	if e.cursorPos == pos {
-		e.expandPeople = !e.expandPeople
-		return
+	// The related section sits above the type sections, so keep the cursor on
+	// the same entity when it changes size
+	if inRelated {
+		e.cursorPos = 0
+	} else {
+		e.cursorPos += e.relatedRows() - before
 	}
-	pos++
-	if e.expandPeople {
-		pos += len(e.people)
+	if e.cursorPos < 0 {
+		e.cursorPos = 0
 	}
+	e.ensureVisible()
+}
 
-	if e.cursorPos == pos {
-		e.expandTools = !e.expandTools
-		return
+func (e *EntitySidebar) relatedRows() int {
+	if e.related == nil {
+		return 0
 	}
-	pos++
-	if e.expandTools {
-		pos += len(e.tools)
-	}
-
-	if e.cursorPos == pos {
-		e.expandConcepts = !e.expandConcepts
-		return
+	if !e.related.expanded {
+		return 1
 	}
+	return 1 + len(e.related.items)
 }
 
-func (e *EntitySidebar) itemAtCursor() *EntityItem {
+func (e *EntitySidebar) ToggleSection() {
 	pos := 0
-
-	// People header
-	if e.cursorPos == pos {
-		return nil
-	}
-	pos++
-
-	if e.expandPeople {
-		for i := range e.people {
-			if e.cursorPos == pos {
-				return &e.people[i]
-			}
-			pos++
+	for _, section := range e.allSections() {
+		if e.cursorPos == pos {
+			section.expanded = !section.expanded
+			return
 		}
-	}
-
-	// Tools header
-	if e.cursorPos == pos {
-		return nil
-	}
-	pos++
-
-	if e.expandTools {
-		for i := range e.tools {
-			if e.cursorPos == pos {
-				return &e.tools[i]
-			}
-			pos++
+		pos++
+		if section.expanded {
+			pos += len(section.items)
 		}
 	}
+}
 
-	// Concepts header
-	if e.cursorPos == pos {
-		return nil
-	}
-	pos++
-
-	if e.expandConcepts {
-		for i := range e.concepts {
-			if e.cursorPos == pos {
-				return &e.concepts[i]
+// ItemAtCursor returns the entity under the cursor, or nil on a section header
+func (e *EntitySidebar) ItemAtCursor() *EntityItem {
+	pos := 0
+	for _, section := range e.allSections() {
+		if e.cursorPos == pos {
+			return nil
+		}
+		pos++
+		if section.expanded {
+			for j := range section.items {
+				if e.cursorPos == pos {
+					return &section.items[j]
+				}
+				pos++
 			}
-			pos++
 		}
 	}
-
 	return nil
 }
 
@@ -248,63 +305,23 @@ func (e *EntitySidebar) View() string {
 	var lines []string
 	pos := 0
 
-	// People section
-	arrow := "▶"
-	if e.expandPeople {
-		arrow = "▼"
-	}
-	header := entityTitleStyle.Render(fmt.Sprintf("%s People (%d)", arrow, len(e.people)))
-	if e.cursorPos == pos {
-		header = lipgloss.NewStyle().Background(lipgloss.Color("#383838")).Render(header)
-	}
-	lines = append(lines, header)
-	pos++
-
-	if e.expandPeople {
-		for _, item := range e.people {
-			line := e.renderEntityItem(item, e.cursorPos == pos)
-			lines = append(lines, line)
-			pos++
+	for _, section := range e.allSections() {
+		arrow := "▶"
+		if section.expanded {
+			arrow = "▼"
 		}
-	}
-
-	// Tools section
-	arrow = "▶"
-	if e.expandTools {
-		arrow = "▼"
-	}
-	header = entityTitleStyle.Render(fmt.Sprintf("%s Tools (%d)", arrow, len(e.tools)))
-	if e.cursorPos == pos {
-		header = lipgloss.NewStyle().Background(lipgloss.Color("#383838")).Render(header)
-	}
-	lines = append(lines, header)
-	pos++
-
-	if e.expandTools {
-		for _, item := range e.tools {
-			line := e.renderEntityItem(item, e.cursorPos == pos)
-			lines = append(lines, line)
-			pos++
+		header := entityTitleStyle.Render(fmt.Sprintf("%s %s (%d)", arrow, section.title, len(section.items)))
+		if e.focused && e.cursorPos == pos {
+			header = lipgloss.NewStyle().Background(lipgloss.Color("#383838")).Render(header)
 		}
-	}
+		lines = append(lines, header)
+		pos++
 
-	// Concepts section
-	arrow = "▶"
-	if e.expandConcepts {
-		arrow = "▼"
-	}
-	header = entityTitleStyle.Render(fmt.Sprintf("%s Concepts (%d)", arrow, len(e.concepts)))
-	if e.cursorPos == pos {
-		header = lipgloss.NewStyle().Background(lipgloss.Color("#383838")).Render(header)
-	}
-	lines = append(lines, header)
-	pos++
-
-	if e.expandConcepts {
-		for _, item := range e.concepts {
-			line := e.renderEntityItem(item, e.cursorPos == pos)
-			lines = append(lines, line)
-			pos++
+		if section.expanded {
+			for _, item := range section.items {
+				lines = append(lines, e.renderEntityItem(item, e.focused && e.cursorPos == pos, section == e.related))
+				pos++
+			}
 		}
 	}
 
@@ -332,13 +349,20 @@ func (e *EntitySidebar) View() string {
 		Render(strings.Join(visibleLines, "\n"))
 }
 
-func (e *EntitySidebar) renderEntityItem(item EntityItem, focused bool) string {
-	selected := e.selectedEntity != nil && *e.selectedEntity == item.Name
+func (e *EntitySidebar) renderEntityItem(item EntityItem, focused, related bool) string {
+	selected := e.selectedEntity != nil && *e.selectedEntity == item.ID
 
 	countStr := facetCountStyle.Render(fmt.Sprintf("(%d)", item.Count))
+	if related {
+		countStr = facetCountStyle.Render(fmt.Sprintf("(%d, %.2f)", item.Count, item.NPMI))
+	}
+
+	marked := e.markedEntity != nil && *e.markedEntity == item.Name
 
 	var line string
-	if selected {
+	if marked {
+		line = entitySelectedStyle.Render(fmt.Sprintf("↳ %s", item.Name))
+	} else if selected {
 		line = entitySelectedStyle.Render(fmt.Sprintf("  %s", item.Name))
 	} else {
 		line = entityItemStyle.Render(fmt.Sprintf("  %s", item.Name))
@@ -355,12 +379,13 @@ func (e *EntitySidebar) renderEntityItem(item EntityItem, focused bool) string {
 	return result
 }
 
-func FilterByEntity(beats []model.EnrichedBeat, entityName *string, entityIndex map[string][]string) []model.EnrichedBeat {
-	if entityName == nil {
+// FilterByEntity keeps the beats mentioning an entity, given by ID
+func FilterByEntity(beats []model.EnrichedBeat, entityID *string, entityIndex map[string][]string) []model.EnrichedBeat {
+	if entityID == nil {
 		return beats
 	}
 
-	beatIDs := entityIndex[*entityName]
+	beatIDs := entityIndex[*entityID]
 	if len(beatIDs) == 0 {
 		return nil
 	}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/bierlingm/beats_viewer/pkg/model"
+	"github.com/bierlingm/beats_viewer/pkg/taxonomy"
 
 	"github.com/charmbracelet/lipgloss"
 )
@@ -24,14 +25,23 @@ var (
 
 	facetCountStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#626262"))
+
+	facetManualStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#FFD75F"))
 )
 
 type FacetSidebar struct {
 	width  int
 	height int
 
+	channels      []model.Channel // from the taxonomy definition, in order
+	sources       []model.Source
+	channelColors map[model.Channel]string
+
 	channelCounts map[model.Channel]int
 	sourceCounts  map[model.Source]int
+	channelManual map[model.Channel]int // beats classified by hand
+	sourceManual  map[model.Source]int
 
 	selectedChannel *model.Channel
 	selectedSource  *model.Source
@@ -41,11 +51,17 @@ type FacetSidebar struct {
 }
 
 func NewFacetSidebar(width, height int) *FacetSidebar {
+	def := taxonomy.BuiltinDefinition()
 	return &FacetSidebar{
 		width:         width,
 		height:        height,
+		channels:      def.ChannelList(),
+		sources:       def.SourceList(),
+		channelColors: def.ChannelColors(),
 		channelCounts: make(map[model.Channel]int),
 		sourceCounts:  make(map[model.Source]int),
+		channelManual: make(map[model.Channel]int),
+		sourceManual:  make(map[model.Source]int),
 		focusChannel:  true,
 		cursorPos:     0,
 	}
@@ -56,13 +72,45 @@ func (f *FacetSidebar) SetSize(width, height int) {
 	f.height = height
 }
 
+// SetDefinition sets the channels and sources listed, keeping selections
+// that still exist
+func (f *FacetSidebar) SetDefinition(def *taxonomy.Definition) {
+	f.channels = def.ChannelList()
+	f.sources = def.SourceList()
+	f.channelColors = def.ChannelColors()
+	if f.selectedChannel != nil {
+		if _, err := def.ParseChannel(f.selectedChannel.String()); err != nil {
+			f.selectedChannel = nil
+		}
+	}
+	if f.selectedSource != nil {
+		if _, err := def.ParseSource(f.selectedSource.String()); err != nil {
+			f.selectedSource = nil
+		}
+	}
+	if maxPos := len(f.channels) + len(f.sources) + 1; f.cursorPos > maxPos {
+		f.cursorPos = maxPos
+	}
+}
+
 func (f *FacetSidebar) UpdateCounts(beats []model.EnrichedBeat) {
 	f.channelCounts = make(map[model.Channel]int)
 	f.sourceCounts = make(map[model.Source]int)
+	f.channelManual = make(map[model.Channel]int)
+	f.sourceManual = make(map[model.Source]int)
 
+	// A beat counts under each of its labels, as it does when filtering
 	for _, eb := range beats {
-		f.channelCounts[eb.Taxonomy.Channel]++
-		f.sourceCounts[eb.Taxonomy.Source]++
+		for _, ch := range eb.Taxonomy.ChannelLabels() {
+			f.channelCounts[ch]++
+		}
+		for _, src := range eb.Taxonomy.SourceLabels() {
+			f.sourceCounts[src]++
+		}
+		if eb.Taxonomy.Manual {
+			f.channelManual[eb.Taxonomy.Channel]++
+			f.sourceManual[eb.Taxonomy.Source]++
+		}
 	}
 }
 
@@ -74,8 +122,9 @@ func (f *FacetSidebar) SelectedSource() *model.Source {
 	return f.selectedSource
 }
 
+// SelectChannelByNumber toggles the nth channel, counting from 1
 func (f *FacetSidebar) SelectChannelByNumber(n int) {
-	channels := model.AllChannels()
+	channels := f.channels
 	if n >= 1 && n <= len(channels) {
 		ch := channels[n-1]
 		if f.selectedChannel != nil && *f.selectedChannel == ch {
@@ -98,15 +147,15 @@ func (f *FacetSidebar) CursorUp() {
 }
 
 func (f *FacetSidebar) CursorDown() {
-	maxPos := len(model.AllChannels()) + len(model.AllSources()) + 1
+	maxPos := len(f.channels) + len(f.sources) + 1
 	if f.cursorPos < maxPos {
 		f.cursorPos++
 	}
 }
 
 func (f *FacetSidebar) ToggleSelection() {
-	channels := model.AllChannels()
-	sources := model.AllSources()
+	channels := f.channels
+	sources := f.sources
 
 	if f.cursorPos == 0 {
 		f.selectedChannel = nil
@@ -148,16 +197,20 @@ func (f *FacetSidebar) View() string {
 
 	total := f.TotalCount()
 	allSelected := f.selectedChannel == nil
-	allLine := f.renderFacetItem("All", total, allSelected, f.cursorPos == 0)
+	allLine := f.renderFacetItem("All", "", total, 0, allSelected, f.cursorPos == 0)
 	sb.WriteString(allLine)
 	sb.WriteString("\n")
 
-	channels := model.AllChannels()
+	channels := f.channels
 	for i, ch := range channels {
 		count := f.channelCounts[ch]
 		selected := f.selectedChannel != nil && *f.selectedChannel == ch
 		focused := f.cursorPos == i+1
-		line := f.renderFacetItem(fmt.Sprintf("%d %s", i+1, ch.String()), count, selected, focused)
+		label := ch.String()
+		if i < 9 {
+			label = fmt.Sprintf("%d %s", i+1, label)
+		}
+		line := f.renderFacetItem(label, f.channelColors[ch], count, f.channelManual[ch], selected, focused)
 		sb.WriteString(line)
 		sb.WriteString("\n")
 	}
@@ -168,15 +221,14 @@ func (f *FacetSidebar) View() string {
 
 	allSourceSelected := f.selectedSource == nil
 	sourceAllFocused := f.cursorPos == len(channels)+1
-	sb.WriteString(f.renderFacetItem("All", total, allSourceSelected, sourceAllFocused))
+	sb.WriteString(f.renderFacetItem("All", "", total, 0, allSourceSelected, sourceAllFocused))
 	sb.WriteString("\n")
 
-	sources := model.AllSources()
-	for i, src := range sources {
+	for i, src := range f.sources {
 		count := f.sourceCounts[src]
 		selected := f.selectedSource != nil && *f.selectedSource == src
 		focused := f.cursorPos == len(channels)+2+i
-		line := f.renderFacetItem(src.String(), count, selected, focused)
+		line := f.renderFacetItem(src.String(), "", count, f.sourceManual[src], selected, focused)
 		sb.WriteString(line)
 		sb.WriteString("\n")
 	}
@@ -188,19 +240,33 @@ func (f *FacetSidebar) View() string {
 		Render(sb.String())
 }
 
-func (f *FacetSidebar) renderFacetItem(label string, count int, selected, focused bool) string {
+// renderFacetItem renders a facet with its beat count, marking how many of
+// those beats were classified by hand. The bullet takes the facet's color.
+func (f *FacetSidebar) renderFacetItem(label, color string, count, manual int, selected, focused bool) string {
 	bullet := "○"
 	if selected {
 		bullet = "●"
 	}
+	bulletStyle := facetItemStyle
+	if selected {
+		bulletStyle = facetSelectedStyle
+	}
+	if color != "" {
+		bulletStyle = bulletStyle.Foreground(lipgloss.Color(color))
+	}
 
 	countStr := facetCountStyle.Render(fmt.Sprintf("(%d)", count))
+	if manual > 0 {
+		countStr = facetCountStyle.Render(fmt.Sprintf("(%d", count)) +
+			facetManualStyle.Render(fmt.Sprintf(" ✎%d", manual)) +
+			facetCountStyle.Render(")")
+	}
 
 	var line string
 	if selected {
-		line = facetSelectedStyle.Render(fmt.Sprintf("%s %s", bullet, label))
+		line = bulletStyle.Render(bullet) + facetSelectedStyle.Render(" "+label)
 	} else {
-		line = facetItemStyle.Render(fmt.Sprintf("%s %s", bullet, label))
+		line = bulletStyle.Render(bullet) + facetItemStyle.Render(" "+label)
 	}
 
 	result := fmt.Sprintf("%s %s", line, countStr)
@@ -214,6 +280,8 @@ func (f *FacetSidebar) renderFacetItem(label string, count int, selected, focuse
 	return result
 }
 
+// FilterByFacets keeps the beats labeled with the channel and source, as
+// their best or a secondary label
 func FilterByFacets(beats []model.EnrichedBeat, channel *model.Channel, source *model.Source) []model.EnrichedBeat {
 	if channel == nil && source == nil {
 		return beats
@@ -221,10 +289,10 @@ func FilterByFacets(beats []model.EnrichedBeat, channel *model.Channel, source *
 
 	var filtered []model.EnrichedBeat
 	for _, eb := range beats {
-		if channel != nil && eb.Taxonomy.Channel != *channel {
+		if channel != nil && !eb.Taxonomy.HasChannel(*channel) {
 			continue
 		}
-		if source != nil && eb.Taxonomy.Source != *source {
+		if source != nil && !eb.Taxonomy.HasSource(*source) {
 			continue
 		}
 		filtered = append(filtered, eb)
@@ -2,9 +2,11 @@ package ui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/bierlingm/beats_viewer/pkg/model"
+	"github.com/bierlingm/beats_viewer/pkg/ripeness"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/lipgloss"
@@ -14,17 +16,25 @@ type DetailView struct {
 	viewport viewport.Model
 	beat     *model.Beat
 	project  string
-	width    int
-	height   int
+	ripeness *ripeness.Explanation
+	taxonomy *model.Taxonomy
+	beads    map[string]model.BeadStatus // linked bead statuses, by bead ID
+
+	mentions       []model.Mention
+	currentMention int // index into mentions, -1 when not jumping
+	contentLine    int // line where the content starts in the rendered view
+	width          int
+	height         int
 }
 
 func NewDetailView(width, height int) DetailView {
 	vp := viewport.New(width, height)
 	vp.Style = lipgloss.NewStyle().Padding(1)
 	return DetailView{
-		viewport: vp,
-		width:    width,
-		height:   height,
+		viewport:       vp,
+		width:          width,
+		height:         height,
+		currentMention: -1,
 	}
 }
 
@@ -47,6 +57,77 @@ func (d *DetailView) SetBeat(beat *model.Beat, project string) {
 	}
 }
 
+// SetMentions sets the entity mentions highlighted for the next beat
+func (d *DetailView) SetMentions(mentions []model.Mention) {
+	d.mentions = mentions
+	d.currentMention = -1
+}
+
+// NextMention moves to the next entity mention, wrapping around, and returns it
+func (d *DetailView) NextMention() (model.Mention, int, bool) {
+	if len(d.mentions) == 0 {
+		return model.Mention{}, 0, false
+	}
+	d.currentMention = (d.currentMention + 1) % len(d.mentions)
+	return d.jumpToMention()
+}
+
+// PrevMention moves to the previous entity mention, wrapping around, and returns it
+func (d *DetailView) PrevMention() (model.Mention, int, bool) {
+	if len(d.mentions) == 0 {
+		return model.Mention{}, 0, false
+	}
+	if d.currentMention <= 0 {
+		d.currentMention = len(d.mentions) - 1
+	} else {
+		d.currentMention--
+	}
+	return d.jumpToMention()
+}
+
+// CurrentMention returns the mention last jumped to with NextMention or PrevMention
+func (d *DetailView) CurrentMention() (model.Mention, bool) {
+	if d.currentMention < 0 || d.currentMention >= len(d.mentions) {
+		return model.Mention{}, false
+	}
+	return d.mentions[d.currentMention], true
+}
+
+// MentionCount returns how many entity mentions the current beat has
+func (d *DetailView) MentionCount() int {
+	return len(d.mentions)
+}
+
+func (d *DetailView) jumpToMention() (model.Mention, int, bool) {
+	mention := d.mentions[d.currentMention]
+	d.viewport.SetContent(d.renderContent())
+
+	line := d.contentLine
+	if d.beat != nil && mention.Start <= len(d.beat.Content) {
+		line += strings.Count(d.beat.Content[:mention.Start], "\n")
+	}
+	if line > 0 {
+		line--
+	}
+	d.viewport.SetYOffset(line)
+	return mention, d.currentMention, true
+}
+
+// SetRipeness sets the ripeness explanation shown for the next beat
+func (d *DetailView) SetRipeness(exp *ripeness.Explanation) {
+	d.ripeness = exp
+}
+
+// SetBeadStatuses sets the status, priority and title of linked beads
+func (d *DetailView) SetBeadStatuses(statuses map[string]model.BeadStatus) {
+	d.beads = statuses
+}
+
+// SetTaxonomy sets the classification shown for the next beat
+func (d *DetailView) SetTaxonomy(t *model.Taxonomy) {
+	d.taxonomy = t
+}
+
 func (d *DetailView) renderContent() string {
 	if d.beat == nil {
 		return "No beat selected"
@@ -78,6 +159,22 @@ func (d *DetailView) renderContent() string {
 	sb.WriteString(ImpetusStyle.Render(d.beat.ImpetusLabel()))
 	sb.WriteString("\n")
 
+	if d.taxonomy != nil {
+		sb.WriteString(DetailLabelStyle.Render("Class: "))
+		sb.WriteString(DetailValueStyle.Render(fmt.Sprintf("%s · %s", d.taxonomy.Channel, d.taxonomy.Source)))
+		if d.taxonomy.Manual {
+			sb.WriteString(ActionPhraseStyle.Render("  ✎ manual"))
+		} else {
+			sb.WriteString(SubtitleStyle.Render(fmt.Sprintf("  auto %.0f%% (v/V to set)", d.taxonomy.Confidence*100)))
+		}
+		sb.WriteString("\n")
+		if also := secondaryLabels(*d.taxonomy); also != "" {
+			sb.WriteString(DetailLabelStyle.Render("Also: "))
+			sb.WriteString(DetailValueStyle.Render(also))
+			sb.WriteString("\n")
+		}
+	}
+
 	if d.beat.Impetus.Raw != "" {
 		sb.WriteString(DetailLabelStyle.Render("Raw: "))
 		sb.WriteString(DetailValueStyle.Render(d.beat.Impetus.Raw))
@@ -95,9 +192,33 @@ func (d *DetailView) renderContent() string {
 	sb.WriteString("\n")
 	sb.WriteString(DetailLabelStyle.Render("Content:"))
 	sb.WriteString("\n")
-	sb.WriteString(ContentStyle.Render(d.beat.Content))
+	d.contentLine = strings.Count(sb.String(), "\n")
+	sb.WriteString(d.renderHighlightedContent())
 	sb.WriteString("\n")
 
+	if len(d.mentions) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(DetailLabelStyle.Render("Mentions: "))
+		seen := make(map[string]bool)
+		var names []string
+		for _, m := range d.mentions {
+			key := strings.ToLower(m.Name) + "-" + m.Type.String()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			names = append(names, EntityStyle(m.Type).Render(m.Name))
+		}
+		sb.WriteString(strings.Join(names, " "))
+		sb.WriteString(SubtitleStyle.Render(fmt.Sprintf("  (n/N: jump %d)", len(d.mentions))))
+		sb.WriteString("\n")
+	}
+
+	if d.ripeness != nil {
+		sb.WriteString("\n")
+		sb.WriteString(d.renderRipeness())
+	}
+
 	if len(d.beat.Entities) > 0 {
 		sb.WriteString("\n")
 		sb.WriteString(DetailLabelStyle.Render("Entities: "))
@@ -113,9 +234,12 @@ func (d *DetailView) renderContent() string {
 
 	if len(d.beat.LinkedBeads) > 0 {
 		sb.WriteString("\n")
-		sb.WriteString(DetailLabelStyle.Render("Linked Beads: "))
-		sb.WriteString(DetailValueStyle.Render(strings.Join(d.beat.LinkedBeads, ", ")))
+		sb.WriteString(DetailLabelStyle.Render("Linked Beads:"))
 		sb.WriteString("\n")
+		for _, id := range d.beat.LinkedBeads {
+			sb.WriteString(DetailValueStyle.Render("  " + beadLine(id, d.beads)))
+			sb.WriteString("\n")
+		}
 	} else {
 		sb.WriteString("\n")
 		sb.WriteString(SubtitleStyle.Render("No linked beads"))
@@ -125,6 +249,119 @@ func (d *DetailView) renderContent() string {
 	return sb.String()
 }
 
+const ripenessBarWidth = 10
+
+func (d *DetailView) renderRipeness() string {
+	exp := d.ripeness
+	b := exp.Breakdown
+
+	var sb strings.Builder
+	sb.WriteString(DetailLabelStyle.Render("Ripeness: "))
+	sb.WriteString(DetailValueStyle.Render(fmt.Sprintf("%s %.2f %s", b.State.Emoji(), b.Total, b.State)))
+	if b.Decay < 1.0 {
+		sb.WriteString(SubtitleStyle.Render(fmt.Sprintf(" (decay x%.2f)", b.Decay)))
+	}
+	sb.WriteString("\n")
+
+	for _, f := range exp.Factors {
+		filled := 0
+		if f.Max > 0 {
+			filled = int(f.Value/f.Max*ripenessBarWidth + 0.5)
+		}
+		if filled > ripenessBarWidth {
+			filled = ripenessBarWidth
+		}
+		sb.WriteString(fmt.Sprintf("  %-13s", f.Name))
+		sb.WriteString(RipenessBarStyle.Render(strings.Repeat("█", filled)))
+		sb.WriteString(RipenessBarEmptyStyle.Render(strings.Repeat("░", ripenessBarWidth-filled)))
+		sb.WriteString(SubtitleStyle.Render(fmt.Sprintf(" %.2f/%.2f", f.Value, f.Max)))
+		sb.WriteString("\n")
+	}
+
+	for _, reason := range exp.Reasons {
+		sb.WriteString(SubtitleStyle.Render("  • " + reason))
+		sb.WriteString("\n")
+	}
+
+	if exp.Advice != "" {
+		sb.WriteString(DetailLabelStyle.Render("To ripen: "))
+		sb.WriteString(DetailValueStyle.Render(exp.Advice))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+type contentSpan struct {
+	start, end int
+	style      lipgloss.Style
+}
+
+// renderHighlightedContent renders content with entity mentions coloured by
+// type, the current mention reversed, and action phrases emphasised
+func (d *DetailView) renderHighlightedContent() string {
+	content := d.beat.Content
+	var spans []contentSpan
+
+	for i, m := range d.mentions {
+		if m.Start < 0 || m.End > len(content) || m.Start >= m.End {
+			continue
+		}
+		style := EntityStyle(m.Type)
+		if i == d.currentMention {
+			style = style.Inherit(CurrentMentionStyle)
+		}
+		spans = append(spans, contentSpan{m.Start, m.End, style})
+	}
+
+	for _, a := range ripeness.ActionPhraseSpans(content) {
+		overlaps := false
+		for _, m := range spans {
+			if a[0] < m.end && m.start < a[1] {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			spans = append(spans, contentSpan{a[0], a[1], ActionPhraseStyle})
+		}
+	}
+
+	if len(spans) == 0 {
+		return ContentStyle.Render(content)
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	var sb strings.Builder
+	last := 0
+	for _, span := range spans {
+		if span.start < last {
+			continue
+		}
+		if span.start > last {
+			sb.WriteString(renderLines(ContentStyle, content[last:span.start]))
+		}
+		sb.WriteString(renderLines(span.style, content[span.start:span.end]))
+		last = span.end
+	}
+	if last < len(content) {
+		sb.WriteString(renderLines(ContentStyle, content[last:]))
+	}
+	return sb.String()
+}
+
+// renderLines styles each line separately, since lipgloss pads multi-line
+// strings to a block and the pieces here are joined inline
+func renderLines(style lipgloss.Style, text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = style.Render(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (d *DetailView) View() string {
 	return d.viewport.View()
 }
@@ -152,3 +389,38 @@ func (d *DetailView) GotoTop() {
 func (d *DetailView) GotoBottom() {
 	d.viewport.GotoBottom()
 }
+
+// secondaryLabels lists a taxonomy's secondary channels and sources with
+// their share of the scores
+func secondaryLabels(t model.Taxonomy) string {
+	var parts []string
+	for _, ch := range t.SecondaryChannels {
+		parts = append(parts, fmt.Sprintf("%s %.0f%%", ch, labelShare(t.Channels, string(ch))*100))
+	}
+	for _, src := range t.SecondarySources {
+		parts = append(parts, fmt.Sprintf("%s %.0f%%", src, labelShare(t.Sources, string(src))*100))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func labelShare(scores []model.LabelScore, name string) float64 {
+	for _, s := range scores {
+		if s.Name == name {
+			return s.Score
+		}
+	}
+	return 0
+}
+
+// beadLine shows a linked bead with its status, priority and title when known
+func beadLine(id string, statuses map[string]model.BeadStatus) string {
+	status, ok := statuses[id]
+	if !ok || status.Status == "" {
+		return id
+	}
+	line := fmt.Sprintf("%s [%s P%d]", id, status.Status, status.Priority)
+	if status.Title != "" {
+		line += " " + status.Title
+	}
+	return line
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/bierlingm/beats_viewer/pkg/loader"
 	"github.com/bierlingm/beats_viewer/pkg/model"
+	"github.com/bierlingm/beats_viewer/pkg/taxonomy"
+	"github.com/bierlingm/beats_viewer/pkg/watch"
 
 	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/list"
@@ -30,6 +32,7 @@ const (
 	focusSearch
 	focusHelp
 	focusProjectPicker
+	focusEntities
 )
 
 type Model struct {
@@ -80,9 +83,45 @@ type beatsLoadedMsg struct {
 	cache         *model.Cache
 	beatToProject map[string]string
 	projects      []model.Project
+	contexts      map[string]*projectContext // by beats directory
+	beatDirs      map[string]string          // beat ID to its project's beats directory
+	taxonomy      *taxonomy.Definition
+	warning       error // the last problem loading left out, such as bead data
 	err           error
 }
 
+// beatsFileChangedMsg reports that watched beats files changed on disk
+type beatsFileChangedMsg struct {
+	watcher *watch.Watcher
+	paths   []string
+}
+
+// beatsChangedMsg carries the beats reloaded after their files changed
+type beatsChangedMsg struct {
+	loaded beatsLoadedMsg
+}
+
+// beadCreatedMsg reports a bead created from a beat
+type beadCreatedMsg struct {
+	beatID string
+	beadID string
+	err    error
+}
+
+// epicCreatedMsg reports an epic bead created from a cluster or chain
+type epicCreatedMsg struct {
+	group    string // e.g. cluster "Ollama tooling"
+	epicID   string
+	children int
+	err      error
+}
+
+// beadStatusesMsg carries what bd reports about the loaded beats' beads
+type beadStatusesMsg struct {
+	statuses map[string]model.BeadStatus
+	err      error
+}
+
 func (m Model) loadBeatsCmd() tea.Cmd {
 	return func() tea.Msg {
 		projects, err := loader.DiscoverProjects(m.rootPath)
@@ -3,16 +3,23 @@ package ui
 import (
 	"fmt"
 	"io"
-	"os/exec"
+	"path/filepath"
 	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/bierlingm/beats_viewer/pkg/bead"
 	"github.com/bierlingm/beats_viewer/pkg/chain"
 	"github.com/bierlingm/beats_viewer/pkg/cluster"
+	"github.com/bierlingm/beats_viewer/pkg/entity"
 	"github.com/bierlingm/beats_viewer/pkg/loader"
 	"github.com/bierlingm/beats_viewer/pkg/model"
+	"github.com/bierlingm/beats_viewer/pkg/ripeness"
+	"github.com/bierlingm/beats_viewer/pkg/taxonomy"
 	"github.com/bierlingm/beats_viewer/pkg/ui/components"
 	"github.com/bierlingm/beats_viewer/pkg/ui/views"
+	"github.com/bierlingm/beats_viewer/pkg/watch"
 
 	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/list"
@@ -28,6 +35,8 @@ const (
 	ViewClusters
 	ViewReview
 	ViewCapture
+	ViewEntity
+	ViewClassify
 )
 
 type ModelV2 struct {
@@ -49,7 +58,9 @@ type ModelV2 struct {
 	timelineView *views.TimelineView
 	clusterView  *views.ClusterView
 	reviewView   *views.StaleReviewView
+	classifyView *views.ClassifyReviewView
 	captureView  *views.CaptureView
+	entityView   *views.EntityProfileView
 
 	chainStore    *chain.Store
 	clusterEngine *cluster.Engine
@@ -62,15 +73,55 @@ type ModelV2 struct {
 	showEntities bool
 
 	sortByRipeness bool
+	beadFilter     components.BeadFilter
+	beadStatuses   map[string]model.BeadStatus // linked beads, by ID
+	ripenessTiers  model.RipenessThresholds
+	contexts       map[string]*projectContext // by beats directory
+	beatDirs       map[string]string          // beat ID to its project's beats directory
+	taxonomy       *taxonomy.Definition
 
 	width  int
 	height int
 
 	statusMsg string
 	rootPath  string
+
+	watcher      *watch.Watcher
+	watchedPaths []string
+	newBeats     map[string]bool // beats that arrived while running, until viewed
+}
+
+// loadWarning keeps the loader's last warning for the status bar, since
+// stderr would write over the TUI
+var loadWarning struct {
+	sync.Mutex
+	err error
+}
+
+// takeLoadWarning returns and clears the loader's last warning
+func takeLoadWarning() error {
+	loadWarning.Lock()
+	defer loadWarning.Unlock()
+	err := loadWarning.err
+	loadWarning.err = nil
+	return err
+}
+
+// withWarning adds a warning, if there is one, to a status message
+func withWarning(status string, warning error) string {
+	if warning == nil {
+		return status
+	}
+	return fmt.Sprintf("%s · Warning: %v", status, warning)
 }
 
 func NewModelV2(rootPath string) ModelV2 {
+	loader.Warn = func(err error) {
+		loadWarning.Lock()
+		loadWarning.err = err
+		loadWarning.Unlock()
+	}
+
 	delegate := NewBeatDelegate()
 	l := list.New([]list.Item{}, delegate, 0, 0)
 	l.SetShowTitle(false)
@@ -88,7 +139,9 @@ func NewModelV2(rootPath string) ModelV2 {
 		timelineView:  views.NewTimelineView(80, 20),
 		clusterView:   views.NewClusterView(80, 20),
 		reviewView:    views.NewStaleReviewView(80, 20),
+		classifyView:  views.NewClassifyReviewView(80, 20),
 		captureView:   views.NewCaptureView(60, 15),
+		entityView:    views.NewEntityProfileView(80, 20),
 		chainStore:    chain.NewStore(),
 		clusterEngine: cluster.NewEngine(),
 		focus:         focusList,
@@ -96,6 +149,8 @@ func NewModelV2(rootPath string) ModelV2 {
 		rootPath:      rootPath,
 		allProjects:   false,
 		currentProj:   -1,
+		ripenessTiers: model.DefaultRipenessThresholds,
+		taxonomy:      taxonomy.BuiltinDefinition(),
 	}
 }
 
@@ -105,53 +160,216 @@ func (m ModelV2) Init() tea.Cmd {
 
 func (m ModelV2) loadBeatsCmd() tea.Cmd {
 	return func() tea.Msg {
-		projects, err := loader.DiscoverProjects(m.rootPath)
-		if err != nil {
-			return beatsLoadedMsg{err: err}
+		return m.loadBeats()
+	}
+}
+
+// reloadBeatsCmd loads the beats again after their files changed on disk
+func (m ModelV2) reloadBeatsCmd() tea.Cmd {
+	return func() tea.Msg {
+		return beatsChangedMsg{loaded: m.loadBeats()}
+	}
+}
+
+// loadBeats discovers the projects and loads the beats being viewed: one
+// project's, or every project's, each enriched from its own cache
+func (m ModelV2) loadBeats() beatsLoadedMsg {
+	projects, err := loader.DiscoverProjects(m.rootPath)
+	if err != nil {
+		return beatsLoadedMsg{err: err}
+	}
+
+	viewed := projects
+	if !m.allProjects && m.currentProj >= 0 {
+		if m.currentProj >= len(projects) {
+			return beatsLoadedMsg{projects: projects}
 		}
+		viewed = projects[m.currentProj : m.currentProj+1]
+	}
 
-		if m.allProjects || m.currentProj < 0 {
-			beats, beatToProject, err := loader.LoadAllBeats(m.rootPath)
-			if err != nil {
+	msg := beatsLoadedMsg{
+		beatToProject: make(map[string]string),
+		projects:      projects,
+		contexts:      make(map[string]*projectContext, len(viewed)),
+		beatDirs:      make(map[string]string),
+		taxonomy:      taxonomy.BuiltinDefinition(),
+	}
+	for _, p := range viewed {
+		enrichedBeats, cache, err := loader.LoadEnrichedBeats(p.Path, nil)
+		if err != nil {
+			if len(viewed) == 1 {
 				return beatsLoadedMsg{err: err, projects: projects}
 			}
+			continue // show the other projects
+		}
+		profile, conns := loadRipenessContext(p.Path, enrichedBeats, cache)
+		msg.contexts[p.Path] = &projectContext{cache: cache, profile: profile, connections: conns}
+		for _, eb := range enrichedBeats {
+			msg.beats = append(msg.beats, eb.Beat)
+			msg.beatToProject[eb.ID] = p.Name
+			msg.beatDirs[eb.ID] = p.Path
+		}
+		msg.enrichedBeats = append(msg.enrichedBeats, enrichedBeats...)
+		// Entities, clusters and chains come from the first project's cache
+		if msg.cache == nil {
+			msg.cache = cache
+			msg.taxonomy = loadTaxonomy(p.Path)
+		}
+	}
 
-			var enrichedBeats []model.EnrichedBeat
-			var cache *model.Cache
-			if len(projects) > 0 {
-				enrichedBeats, cache, _ = loader.LoadEnrichedBeats(projects[0].Path, nil)
-			}
+	if len(viewed) > 1 {
+		sort.SliceStable(msg.beats, func(i, j int) bool {
+			return msg.beats[i].CreatedAt.After(msg.beats[j].CreatedAt)
+		})
+		sort.SliceStable(msg.enrichedBeats, func(i, j int) bool {
+			return msg.enrichedBeats[i].CreatedAt.After(msg.enrichedBeats[j].CreatedAt)
+		})
+	}
+	msg.warning = takeLoadWarning()
+	return msg
+}
 
-			return beatsLoadedMsg{
-				beats:         beats,
-				enrichedBeats: enrichedBeats,
-				cache:         cache,
-				beatToProject: beatToProject,
-				projects:      projects,
-				err:           err,
+// setLoaded takes in freshly loaded beats and refreshes the views built
+// from them
+func (m *ModelV2) setLoaded(msg beatsLoadedMsg) {
+	m.beats = msg.beats
+	m.enrichedBeats = msg.enrichedBeats
+	m.cache = msg.cache
+	m.beatToProject = msg.beatToProject
+	m.projects = msg.projects
+	m.contexts = msg.contexts
+	m.beatDirs = msg.beatDirs
+	if msg.taxonomy != nil {
+		m.taxonomy = msg.taxonomy
+		m.facets.SetDefinition(m.taxonomy)
+		m.timelineView.SetChannelColors(m.taxonomy.ChannelColors())
+		m.captureView.SetOptions(m.taxonomy.ChannelList(), m.taxonomy.SourceList())
+	}
+
+	if m.cache != nil {
+		m.ripenessTiers = m.cache.RipenessTiers
+		m.updateLayout()
+		m.chainStore.LoadFromCache(m.cache.Chains)
+		m.facets.UpdateCounts(m.enrichedBeats)
+		m.entities.UpdateEntities(m.cache.Entities)
+		m.timelineView.SetBeats(m.enrichedBeats)
+		m.timelineView.SetEntities(m.cache.Entities)
+		m.clusterView.SetClusters(m.cache.Clusters)
+		m.clusterView.SetBeatContents(m.enrichedBeats)
+		if p := m.entityView.Profile(); p != nil && m.viewMode == ViewEntity {
+			key := p.ID
+			if key == "" {
+				key = p.Name
 			}
+			m.openEntityProfile(key)
 		}
+	}
+}
 
-		if m.currentProj >= 0 && m.currentProj < len(projects) {
-			enrichedBeats, cache, err := loader.LoadEnrichedBeats(projects[m.currentProj].Path, nil)
-			beatToProject := make(map[string]string)
-			var beats []model.Beat
-			for _, eb := range enrichedBeats {
-				beats = append(beats, eb.Beat)
-				beatToProject[eb.ID] = projects[m.currentProj].Name
-			}
-			return beatsLoadedMsg{
-				beats:         beats,
-				enrichedBeats: enrichedBeats,
-				cache:         cache,
-				beatToProject: beatToProject,
-				projects:      projects,
-				err:           err,
-			}
+// watchBeats watches every discovered project's beats.jsonl, starting over
+// when the projects change, and waits for the next change
+func (m *ModelV2) watchBeats() tea.Cmd {
+	var paths []string
+	for _, p := range m.projects {
+		paths = append(paths, filepath.Join(p.Path, loader.BeatsFile))
+	}
+	if m.watcher != nil && strings.Join(paths, "\n") == strings.Join(m.watchedPaths, "\n") {
+		// Already waiting on these files
+		return nil
+	}
+	if m.watcher != nil {
+		m.watcher.Close()
+	}
+	m.watcher = nil
+	m.watchedPaths = paths
+	if len(paths) == 0 {
+		return nil
+	}
+	m.watcher = watch.New(paths)
+	return waitForChange(m.watcher)
+}
+
+// waitForChange waits in the background for a watched beats file to change
+func waitForChange(w *watch.Watcher) tea.Cmd {
+	if w == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		paths := w.Next()
+		if paths == nil {
+			return nil
 		}
+		return beatsFileChangedMsg{watcher: w, paths: paths}
+	}
+}
 
-		return beatsLoadedMsg{projects: projects}
+// watchesViewedBeats reports whether any changed file holds beats on screen
+func (m ModelV2) watchesViewedBeats(paths []string) bool {
+	if m.allProjects || m.currentProj < 0 {
+		return true
+	}
+	if m.currentProj >= len(m.projects) {
+		return false
 	}
+	viewed := filepath.Join(m.projects[m.currentProj].Path, loader.BeatsFile)
+	for _, p := range paths {
+		if p == viewed {
+			return true
+		}
+	}
+	return false
+}
+
+func pluralBeats(n int) string {
+	if n == 1 {
+		return "beat"
+	}
+	return "beats"
+}
+
+// loadTaxonomy loads the project's taxonomy definition, falling back to the
+// built-in one when its config is invalid
+func loadTaxonomy(beatsDir string) *taxonomy.Definition {
+	def, _, err := taxonomy.LoadDefinition(beatsDir)
+	if err != nil {
+		return taxonomy.BuiltinDefinition()
+	}
+	return def
+}
+
+// projectContext is what a project's beats are scored with: its cache, which
+// holds their view counts, its ripeness profile and its related beats
+type projectContext struct {
+	cache       *model.Cache
+	profile     ripeness.Profile
+	connections ripeness.Connections
+}
+
+// projectDir returns the beats directory of the project a beat belongs to,
+// or "" when it is not loaded
+func (m *ModelV2) projectDir(beatID string) string {
+	return m.beatDirs[beatID]
+}
+
+// loadRipenessContext loads the ripeness profile and related beats used to
+// explain scores in the detail view
+func loadRipenessContext(beatsDir string, enrichedBeats []model.EnrichedBeat, cache *model.Cache) (ripeness.Profile, ripeness.Connections) {
+	profile, _, err := ripeness.LoadProfile(beatsDir)
+	if err != nil {
+		profile = ripeness.DefaultProfile()
+	}
+
+	beats := make([]model.Beat, len(enrichedBeats))
+	for i, eb := range enrichedBeats {
+		beats[i] = eb.Beat
+	}
+	var entities []model.Entity
+	var neighbors map[string][]model.Neighbor
+	if cache != nil {
+		entities = cache.Entities
+		neighbors = cache.EmbeddingNeighbors
+	}
+	return profile, ripeness.BuildConnections(beats, entities, neighbors, profile.SimilarityThreshold)
 }
 
 func (m ModelV2) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -167,22 +385,9 @@ func (m ModelV2) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.statusMsg = fmt.Sprintf("Error: %v", msg.err)
 			return m, nil
 		}
-		m.beats = msg.beats
-		m.enrichedBeats = msg.enrichedBeats
+		m.setLoaded(msg)
 		m.filteredBeats = msg.enrichedBeats
-		m.cache = msg.cache
-		m.beatToProject = msg.beatToProject
-		m.projects = msg.projects
-
-		if m.cache != nil {
-			m.chainStore.LoadFromCache(m.cache.Chains)
-			m.facets.UpdateCounts(m.enrichedBeats)
-			m.entities.UpdateEntities(m.cache.Entities)
-			m.timelineView.SetBeats(m.enrichedBeats)
-			m.clusterView.SetClusters(m.cache.Clusters)
-			m.clusterView.SetBeatContents(m.enrichedBeats)
-		}
-
+		m.newBeats = nil
 		m.updateList()
 		if len(m.beats) > 0 {
 			m.updateSelectedBeat()
@@ -192,7 +397,105 @@ func (m ModelV2) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.cache != nil {
 			cacheStatus = " (cache loaded)"
 		}
-		m.statusMsg = fmt.Sprintf("Loaded %d beats from %d projects%s", len(m.beats), len(m.projects), cacheStatus)
+		m.statusMsg = withWarning(fmt.Sprintf("Loaded %d beats from %d projects%s", len(m.beats), len(m.projects), cacheStatus), msg.warning)
+		return m, tea.Batch(m.loadBeadStatusesCmd(), m.watchBeats())
+
+	case beatsFileChangedMsg:
+		if msg.watcher != m.watcher {
+			// From a watcher replaced since
+			return m, nil
+		}
+		if m.watchesViewedBeats(msg.paths) {
+			return m, tea.Batch(m.reloadBeatsCmd(), waitForChange(m.watcher))
+		}
+		return m, waitForChange(m.watcher)
+
+	case beatsChangedMsg:
+		if msg.loaded.err != nil {
+			m.statusMsg = fmt.Sprintf("Error reloading: %v", msg.loaded.err)
+			return m, nil
+		}
+		selected := ""
+		if item, ok := m.list.SelectedItem().(EnrichedBeatItem); ok {
+			selected = item.beat.ID
+		}
+		known := make(map[string]bool, len(m.beats))
+		for _, b := range m.beats {
+			known[b.ID] = true
+		}
+
+		previous := m.contexts
+		m.setLoaded(msg.loaded)
+		// Keep the views recorded since the last load
+		for dir, prev := range previous {
+			cur := m.contexts[dir]
+			if prev.cache == nil || cur == nil || cur.cache == nil || cur.cache.ViewStats == nil {
+				continue
+			}
+			for id, stat := range prev.cache.ViewStats {
+				if stat.ViewCount > cur.cache.ViewStats[id].ViewCount {
+					cur.cache.ViewStats[id] = stat
+				}
+			}
+		}
+		added := 0
+		for _, b := range m.beats {
+			if !known[b.ID] {
+				if m.newBeats == nil {
+					m.newBeats = make(map[string]bool)
+				}
+				m.newBeats[b.ID] = true
+				added++
+			}
+		}
+		m.applyFilters()
+		if selected != "" {
+			m.selectBeatByID(selected)
+		}
+
+		if added > 0 {
+			m.statusMsg = fmt.Sprintf("%d new %s", added, pluralBeats(added))
+		} else {
+			m.statusMsg = "Beats updated"
+		}
+		m.statusMsg = withWarning(m.statusMsg, msg.loaded.warning)
+		return m, tea.Batch(m.loadBeadStatusesCmd(), m.watchBeats())
+
+	case beadCreatedMsg:
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Error: %v", msg.err)
+		} else {
+			m.statusMsg = fmt.Sprintf("Created bead %s from %s", msg.beadID, msg.beatID)
+		}
+		if msg.beadID == "" {
+			return m, nil
+		}
+		return m, m.loadBeatsCmd()
+
+	case epicCreatedMsg:
+		switch {
+		case msg.err != nil && msg.epicID != "":
+			m.statusMsg = fmt.Sprintf("Created epic %s from %s, then: %v", msg.epicID, msg.group, msg.err)
+		case msg.err != nil:
+			m.statusMsg = fmt.Sprintf("Error: %v", msg.err)
+		default:
+			m.statusMsg = fmt.Sprintf("Created epic %s from %s with %d child beads", msg.epicID, msg.group, msg.children)
+		}
+		if msg.epicID == "" {
+			return m, nil
+		}
+		return m, m.loadBeatsCmd()
+
+	case beadStatusesMsg:
+		if msg.err == nil {
+			m.beadStatuses = msg.statuses
+			m.detail.SetBeadStatuses(msg.statuses)
+			if m.beadFilter != components.BeadFilterAll {
+				m.applyFilters()
+			} else {
+				m.updateSelectedBeat()
+			}
+		}
 		return m, nil
 
 	case tea.KeyMsg:
@@ -206,10 +509,30 @@ func (m ModelV2) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if m.viewMode == ViewReview {
+			reviewed := m.reviewView.CurrentBeat()
 			action, cmd := m.reviewView.Update(msg)
+			if reviewed != nil && action.Outcome() != "" {
+				m.recordReview(reviewed.ID, action)
+			}
+			if reviewed != nil && action == views.ReviewConvert {
+				cmd = tea.Batch(cmd, m.convertToBead(*reviewed))
+			}
 			if msg.String() == "q" || m.reviewView.IsComplete() {
 				m.viewMode = ViewList
-				_ = action
+			}
+			return m, cmd
+		}
+
+		if m.viewMode == ViewClassify {
+			decision, cmd := m.classifyView.Update(msg)
+			if decision != nil {
+				m.recordClassification(*decision)
+			}
+			if msg.String() == "q" || msg.String() == "esc" || m.classifyView.IsComplete() {
+				m.viewMode = ViewList
+				if m.classifyView.Completed() > 0 {
+					return m, tea.Batch(cmd, m.loadBeatsCmd())
+				}
 			}
 			return m, cmd
 		}
@@ -234,6 +557,18 @@ func (m ModelV2) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		if m.focus == focusEntities {
+			if handled, cmd := m.handleEntityKey(msg.String()); handled {
+				return m, cmd
+			}
+		}
+
+		if m.viewMode == ViewEntity {
+			if m.handleProfileKey(msg) {
+				return m, nil
+			}
+		}
+
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return m, tea.Quit
@@ -264,9 +599,24 @@ func (m ModelV2) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "e":
 			m.showEntities = !m.showEntities
+			if !m.showEntities {
+				m.setEntityFocus(false)
+			}
 			m.updateLayout()
 			return m, nil
 
+		case "E":
+			if m.width < WidthWide {
+				m.statusMsg = "Entity sidebar needs a wider terminal"
+				return m, nil
+			}
+			if !m.showEntities {
+				m.showEntities = true
+				m.updateLayout()
+			}
+			m.setEntityFocus(m.focus != focusEntities)
+			return m, nil
+
 		case "t":
 			if m.viewMode == ViewTimeline {
 				m.viewMode = ViewList
@@ -289,6 +639,12 @@ func (m ModelV2) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.viewMode = ViewReview
 			return m, nil
 
+		case "L":
+			unsure := views.FindUnsureBeats(m.enrichedBeats)
+			m.classifyView.SetBeats(unsure, m.taxonomy.ChannelList(), m.taxonomy.SourceList())
+			m.viewMode = ViewClassify
+			return m, nil
+
 		case "R":
 			m.sortByRipeness = !m.sortByRipeness
 			m.applyFilters()
@@ -299,7 +655,13 @@ func (m ModelV2) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		case "1", "2", "3", "4", "5", "6", "7":
+		case "B":
+			m.beadFilter = m.beadFilter.Next()
+			m.applyFilters()
+			m.statusMsg = "Beads: " + m.beadFilter.String()
+			return m, nil
+
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
 			n := int(msg.String()[0] - '0')
 			m.facets.SelectChannelByNumber(n)
 			m.applyFilters()
@@ -308,13 +670,31 @@ func (m ModelV2) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "!":
 			m.facets.ClearFilters()
 			m.entities.ClearSelection()
+			m.beadFilter = components.BeadFilterAll
 			m.applyFilters()
 			m.statusMsg = "Filters cleared"
 			return m, nil
 
 		case "b":
+			if m.viewMode == ViewClusters {
+				if c := m.clusterView.SelectedCluster(); c != nil {
+					return m, m.convertToEpic(bead.ClusterGroup(*c, m.enrichedBeats))
+				}
+				return m, nil
+			}
+			if item, ok := m.list.SelectedItem().(EnrichedBeatItem); ok {
+				return m, m.convertToBead(item.beat)
+			}
+			return m, nil
+
+		case "ctrl+b":
 			if item, ok := m.list.SelectedItem().(EnrichedBeatItem); ok {
-				m.convertToBead(item.beat)
+				chains := m.chainStore.GetChainsForBeat(item.beat.ID)
+				if len(chains) == 0 {
+					m.statusMsg = "Beat is not in a chain"
+					return m, nil
+				}
+				return m, m.convertToEpic(bead.ChainGroup(chains[0], m.enrichedBeats))
 			}
 			return m, nil
 
@@ -324,6 +704,36 @@ func (m ModelV2) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		case "v", "V":
+			if item, ok := m.list.SelectedItem().(EnrichedBeatItem); ok {
+				return m, m.reclassify(item.beat, msg.String() == "V")
+			}
+			return m, nil
+
+		case "n", "N":
+			var mention model.Mention
+			var idx int
+			var ok bool
+			if msg.String() == "n" {
+				mention, idx, ok = m.detail.NextMention()
+			} else {
+				mention, idx, ok = m.detail.PrevMention()
+			}
+			if ok {
+				m.statusMsg = fmt.Sprintf("Mention %d/%d: %s (%s)", idx+1, m.detail.MentionCount(), mention.Surface, mention.Type)
+			} else {
+				m.statusMsg = "No entity mentions"
+			}
+			return m, nil
+
+		case "o":
+			if mention, ok := m.detail.CurrentMention(); ok {
+				m.openEntityProfile(mention.EntityID())
+			} else {
+				m.statusMsg = "Press n to choose a mention first"
+			}
+			return m, nil
+
 		case "[":
 			m.navigateChainPrev()
 			return m, nil
@@ -503,19 +913,19 @@ func (m *ModelV2) updateLayout() {
 	if m.width < WidthCompact {
 		m.list.SetSize(m.width, contentHeight)
 		m.detail.SetSize(m.width, contentHeight)
-		delegate := NewEnrichedBeatDelegate().SetWidth(m.width - 4)
+		delegate := NewEnrichedBeatDelegate().SetWidth(m.width - 4).SetTiers(m.ripenessTiers)
 		m.list.SetDelegate(delegate)
 	} else if m.width >= SplitViewThreshold {
 		listWidth := mainWidth / 2
 		detailWidth := mainWidth - listWidth - 2
 		m.list.SetSize(listWidth, contentHeight)
 		m.detail.SetSize(detailWidth, contentHeight-2)
-		delegate := NewEnrichedBeatDelegate().SetWidth(listWidth - 2)
+		delegate := NewEnrichedBeatDelegate().SetWidth(listWidth - 2).SetTiers(m.ripenessTiers)
 		m.list.SetDelegate(delegate)
 	} else {
 		m.list.SetSize(mainWidth, contentHeight)
 		m.detail.SetSize(mainWidth, contentHeight)
-		delegate := NewEnrichedBeatDelegate().SetWidth(mainWidth - 2)
+		delegate := NewEnrichedBeatDelegate().SetWidth(mainWidth - 2).SetTiers(m.ripenessTiers)
 		m.list.SetDelegate(delegate)
 	}
 
@@ -524,7 +934,9 @@ func (m *ModelV2) updateLayout() {
 	m.timelineView.SetSize(mainWidth, contentHeight)
 	m.clusterView.SetSize(mainWidth, contentHeight)
 	m.reviewView.SetSize(mainWidth, contentHeight)
+	m.classifyView.SetSize(mainWidth, contentHeight)
 	m.captureView.SetSize(mainWidth-10, contentHeight-5)
+	m.entityView.SetSize(mainWidth, contentHeight)
 	m.search.SetWidth(m.width / 3)
 }
 
@@ -552,6 +964,8 @@ func (m *ModelV2) applyFilters() {
 		filtered = components.FilterByEntity(filtered, m.entities.SelectedEntity(), m.cache.EntityIndex)
 	}
 
+	filtered = components.FilterByBeads(filtered, m.beadFilter, m.beadStatuses)
+
 	if m.sortByRipeness {
 		sort.Slice(filtered, func(i, j int) bool {
 			return filtered[i].RipenessScore > filtered[j].RipenessScore
@@ -590,20 +1004,297 @@ func (m *ModelV2) filterToBeatIDs(beatIDs []string) {
 func (m *ModelV2) updateSelectedBeat() {
 	if item, ok := m.list.SelectedItem().(EnrichedBeatItem); ok {
 		beat := item.beat.Beat
+		// Explain with the beat's own project, as it was scored
+		profile, conns, stat := ripeness.DefaultProfile(), ripeness.Connections(nil), model.ViewStat{}
+		if ctx := m.contexts[m.projectDir(beat.ID)]; ctx != nil {
+			profile, conns = ctx.profile, ctx.connections
+			if ctx.cache != nil {
+				stat = ctx.cache.ViewStats[beat.ID]
+			}
+		}
+		explanation := profile.Explain(beat, conns, stat)
+		taxonomy := item.beat.Taxonomy
+		m.detail.SetRipeness(&explanation)
+		m.detail.SetTaxonomy(&taxonomy)
+		m.detail.SetMentions(item.beat.Mentions)
 		m.detail.SetBeat(&beat, item.project)
 	}
 }
 
 func (m *ModelV2) recordView() {
 	if item, ok := m.list.SelectedItem().(EnrichedBeatItem); ok {
-		if m.cache != nil {
-			stat := m.cache.ViewStats[item.beat.ID]
+		if ctx := m.contexts[m.projectDir(item.beat.ID)]; ctx != nil && ctx.cache != nil && ctx.cache.ViewStats != nil {
+			stat := ctx.cache.ViewStats[item.beat.ID]
 			stat.ViewCount++
 			now := time.Now()
 			stat.LastViewedAt = &now
-			m.cache.ViewStats[item.beat.ID] = stat
+			ctx.cache.ViewStats[item.beat.ID] = stat
 		}
+		delete(m.newBeats, item.beat.ID)
+	}
+}
+
+// beatsDir returns the .beats directory whose cache backs the current view
+func (m *ModelV2) beatsDir() string {
+	if m.currentProj >= 0 && m.currentProj < len(m.projects) {
+		return m.projects[m.currentProj].Path
+	}
+	if len(m.projects) > 0 {
+		return m.projects[0].Path
 	}
+	return ""
+}
+
+func (m *ModelV2) recordReview(beatID string, action views.ReviewAction) {
+	dir := m.projectDir(beatID)
+	if dir == "" {
+		m.statusMsg = fmt.Sprintf("No project holds %s", beatID)
+		return
+	}
+	decision := model.ReviewDecision{BeatID: beatID, Outcome: action.Outcome(), At: time.Now()}
+	if err := loader.AppendReviewDecision(dir, decision); err != nil {
+		m.statusMsg = fmt.Sprintf("Error: %v", err)
+	}
+}
+
+// recordClassification saves a channel and source confirmed in review as a
+// manual override, so it survives cache rebuilds
+func (m *ModelV2) recordClassification(decision views.ClassifyDecision) {
+	dir := m.projectDir(decision.BeatID)
+	if dir == "" {
+		m.statusMsg = fmt.Sprintf("No project holds %s", decision.BeatID)
+		return
+	}
+	override := model.TaxonomyOverride{
+		Channel: string(decision.Channel),
+		Source:  string(decision.Source),
+		At:      time.Now(),
+	}
+	if err := loader.SetTaxonomyOverride(dir, decision.BeatID, override); err != nil {
+		m.statusMsg = fmt.Sprintf("Error: %v", err)
+		return
+	}
+	m.statusMsg = fmt.Sprintf("%s: %s · %s", decision.BeatID, decision.Channel, decision.Source)
+}
+
+func (m *ModelV2) setEntityFocus(focused bool) {
+	if focused {
+		m.focus = focusEntities
+		m.statusMsg = "Entities: enter filter  o profile  m merge  T retype  P concept  x hide  s split  E/esc back"
+	} else if m.focus == focusEntities {
+		m.focus = focusList
+		m.entities.SetMarked(nil)
+	}
+	m.entities.SetFocused(focused)
+}
+
+// handleEntityKey handles keys while the entity sidebar has focus, reporting
+// whether the key was consumed
+func (m *ModelV2) handleEntityKey(key string) (bool, tea.Cmd) {
+	item := m.entities.ItemAtCursor()
+
+	switch key {
+	case "j", "down":
+		m.entities.CursorDown()
+		return true, nil
+	case "k", "up":
+		m.entities.CursorUp()
+		return true, nil
+	case "esc", "tab":
+		m.setEntityFocus(false)
+		return true, nil
+	case "enter", " ":
+		if item == nil {
+			m.entities.ToggleSection()
+		} else {
+			m.entities.ToggleSelection()
+			m.applyFilters()
+		}
+		return true, nil
+	}
+
+	if item == nil {
+		return false, nil
+	}
+	name, entityType := item.Name, item.Type
+
+	switch key {
+	case "o":
+		m.setEntityFocus(false)
+		m.openEntityProfile(item.ID)
+		return true, nil
+	case "m":
+		marked := m.entities.MarkedEntity()
+		if marked == nil {
+			m.entities.SetMarked(&name)
+			m.statusMsg = fmt.Sprintf("Merging %s: choose the entity to merge into and press m", name)
+			return true, nil
+		}
+		from := *marked
+		m.entities.SetMarked(nil)
+		if from == name {
+			m.statusMsg = "Merge cancelled"
+			return true, nil
+		}
+		return true, m.curateEntities(fmt.Sprintf("Merged %s into %s", from, name), func(c *entity.DictionaryConfig) error {
+			return c.MergeEntity(from, name, entityType)
+		})
+	case "T":
+		next := model.AllEntityTypes()[(int(entityType)+1)%len(model.AllEntityTypes())]
+		return true, m.curateEntities(fmt.Sprintf("%s is now a %s", name, next), func(c *entity.DictionaryConfig) error {
+			c.Retype(name, next)
+			return nil
+		})
+	case "P":
+		return true, m.curateEntities(fmt.Sprintf("Promoted %s to concept", name), func(c *entity.DictionaryConfig) error {
+			c.Promote(name)
+			return nil
+		})
+	case "x":
+		return true, m.curateEntities(fmt.Sprintf("Hid %s", name), func(c *entity.DictionaryConfig) error {
+			c.Hide(name)
+			return nil
+		})
+	case "s":
+		return true, m.curateEntities(fmt.Sprintf("Split merges into %s", name), func(c *entity.DictionaryConfig) error {
+			if len(c.Split(name)) == 0 {
+				return fmt.Errorf("nothing is merged into %s", name)
+			}
+			return nil
+		})
+	}
+	return false, nil
+}
+
+// openEntityProfile shows the profile page of an entity, given by ID or by
+// name to cover every type sharing it
+func (m *ModelV2) openEntityProfile(key string) {
+	if m.cache == nil {
+		return
+	}
+	graph := entity.BuildGraph(m.cache.Entities, 1)
+	profile, ok := entity.BuildProfile(key, m.enrichedBeats, m.cache.Entities, graph, components.MaxRelatedEntities)
+	if !ok {
+		m.statusMsg = fmt.Sprintf("No beats mention %s", key)
+		if m.viewMode == ViewEntity {
+			m.viewMode = ViewList
+		}
+		return
+	}
+	profile.NameChains(m.chainStore.List())
+	profile.NameClusters(m.cache.Clusters)
+	m.entityView.SetProfile(profile)
+	m.viewMode = ViewEntity
+}
+
+// handleProfileKey handles keys on an entity profile page, reporting whether
+// the key was consumed
+func (m *ModelV2) handleProfileKey(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "j", "down", "k", "up", "g", "G":
+		m.entityView.Update(msg)
+		return true
+	case "enter":
+		if id, ok := m.entityView.SelectedRelated(); ok {
+			m.openEntityProfile(id)
+			return true
+		}
+		if beatID, ok := m.entityView.SelectedBeatID(); ok {
+			profile := m.entityView.Profile()
+			m.filterToBeatIDs(profile.BeatIDs)
+			m.viewMode = ViewList
+			m.selectBeatByID(beatID)
+			m.statusMsg = fmt.Sprintf("Showing %d beats mentioning %s", len(profile.BeatIDs), profile.Name)
+		}
+		return true
+	}
+	return false
+}
+
+// curateEntities applies a change to the project's entity curation and
+// reloads, which re-extracts entities with it
+func (m *ModelV2) curateEntities(done string, change func(*entity.DictionaryConfig) error) tea.Cmd {
+	dir := m.beatsDir()
+	if dir == "" {
+		m.statusMsg = "No project to curate"
+		return nil
+	}
+
+	curation, err := entity.LoadCuration(dir)
+	if err == nil {
+		err = change(&curation)
+	}
+	if err == nil {
+		err = entity.SaveCuration(dir, curation)
+	}
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Error: %v", err)
+		return nil
+	}
+
+	m.statusMsg = done
+	return m.loadBeatsCmd()
+}
+
+// reclassify moves a beat's channel, or source, to the next value of its
+// project's taxonomy by hand. Cycling past the last value drops the override,
+// back to the automatic classification.
+func (m *ModelV2) reclassify(beat model.EnrichedBeat, source bool) tea.Cmd {
+	dir := m.projectDir(beat.ID)
+	if dir == "" {
+		m.statusMsg = fmt.Sprintf("No project holds %s", beat.ID)
+		return nil
+	}
+	def := loadTaxonomy(dir)
+
+	overrides, err := loader.LoadTaxonomyOverrides(dir)
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Error: %v", err)
+		return nil
+	}
+	override := overrides[beat.ID]
+	override.At = time.Now()
+
+	facet, value := "Channel", ""
+	if source {
+		var names []string
+		for _, src := range def.SourceList() {
+			names = append(names, src.String())
+		}
+		override.Source = nextOverride(names, override.Source)
+		facet, value = "Source", override.Source
+	} else {
+		var names []string
+		for _, ch := range def.ChannelList() {
+			names = append(names, ch.String())
+		}
+		override.Channel = nextOverride(names, override.Channel)
+		value = override.Channel
+	}
+	if value == "" {
+		value = "automatic"
+	}
+
+	if err := loader.SetTaxonomyOverride(dir, beat.ID, override); err != nil {
+		m.statusMsg = fmt.Sprintf("Error: %v", err)
+		return nil
+	}
+	m.statusMsg = fmt.Sprintf("%s of %s: %s", facet, beat.ID, value)
+	return m.loadBeatsCmd()
+}
+
+// nextOverride returns the value after current, the first one when nothing
+// is set, or "" after the last
+func nextOverride(names []string, current string) string {
+	if current == "" {
+		return names[0]
+	}
+	for i, name := range names {
+		if name == current && i+1 < len(names) {
+			return names[i+1]
+		}
+	}
+	return ""
 }
 
 func (m *ModelV2) cycleProject() {
@@ -614,18 +1305,123 @@ func (m *ModelV2) cycleProject() {
 	m.currentProj = (m.currentProj + 1) % len(m.projects)
 }
 
-func (m *ModelV2) convertToBead(beat model.EnrichedBeat) {
-	content := beat.Content
-	if len(content) > 200 {
-		content = content[:200] + "..."
+// convertToBead creates a bead from a beat with bd, run in the beat's own
+// project, in the background and links the two
+func (m *ModelV2) convertToBead(beat model.EnrichedBeat) tea.Cmd {
+	dir := m.projectDir(beat.ID)
+	if dir == "" {
+		m.statusMsg = fmt.Sprintf("No project holds %s", beat.ID)
+		return nil
 	}
-	title := fmt.Sprintf("Beat: %s", beat.ID)
+	m.statusMsg = fmt.Sprintf("Creating bead from %s...", beat.ID)
+	return func() tea.Msg {
+		id, err := bead.CreateForBeat(dir, beat.Beat)
+		return beadCreatedMsg{beatID: beat.ID, beadID: id, err: err}
+	}
+}
 
-	cmd := exec.Command("bd", "create", title, "-d", content)
-	if err := cmd.Start(); err != nil {
-		m.statusMsg = fmt.Sprintf("Error: %v", err)
-	} else {
-		m.statusMsg = "Opening bd create..."
+// convertToEpic creates an epic bead for a cluster or chain in the
+// background, with child beads for ripe members when configured. bd runs in
+// the project holding most of the members, and each member's link is
+// recorded in its own project.
+func (m *ModelV2) convertToEpic(g bead.Group) tea.Cmd {
+	dir := m.groupProject(g)
+	if dir == "" {
+		m.statusMsg = "No project to create a bead in"
+		return nil
+	}
+	beatDirs := m.beatDirs
+	name := fmt.Sprintf("%s %q", g.Kind, g.Name)
+	m.statusMsg = fmt.Sprintf("Creating epic from %s...", name)
+	return func() tea.Msg {
+		cfg, _, err := bead.LoadConfig(dir)
+		if err != nil {
+			return epicCreatedMsg{group: name, err: err}
+		}
+		projectOf := func(beatID string) string { return beatDirs[beatID] }
+		result, err := bead.CreateEpic(dir, bead.PlanEpic(g, cfg.EpicChildren), g, projectOf)
+		return epicCreatedMsg{group: name, epicID: result.EpicID, children: len(result.Children), err: err}
+	}
+}
+
+// groupProject returns the beats directory holding most of a group's
+// members, the first member's on a tie
+func (m *ModelV2) groupProject(g bead.Group) string {
+	counts := make(map[string]int)
+	best := ""
+	for _, eb := range g.Beats {
+		dir := m.projectDir(eb.ID)
+		if dir == "" {
+			continue
+		}
+		counts[dir]++
+		if best == "" || counts[dir] > counts[best] {
+			best = dir
+		}
+	}
+	return best
+}
+
+// loadBeadStatusesCmd looks up every bead linked to the loaded beats in the
+// bead stores under the root, asking bd in each beat's own project about
+// beads none of them hold
+func (m ModelV2) loadBeadStatusesCmd() tea.Cmd {
+	byProject := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, eb := range m.enrichedBeats {
+		dir := m.projectDir(eb.ID)
+		for _, id := range eb.LinkedBeads {
+			if dir != "" && !seen[id] {
+				seen[id] = true
+				byProject[dir] = append(byProject[dir], id)
+			}
+		}
+	}
+	if len(byProject) == 0 {
+		return nil
+	}
+	root := m.rootPath
+	return func() tea.Msg {
+		statuses := make(map[string]model.BeadStatus)
+		stores, err := loader.DiscoverBeadStores(root)
+		if err != nil {
+			return beadStatusesMsg{err: err}
+		}
+		for _, store := range stores {
+			issues, err := loader.LoadBeadIssues(store.Path)
+			if err != nil {
+				continue
+			}
+			for id := range seen {
+				if status, ok := issues[id]; ok {
+					statuses[id] = status
+				}
+			}
+		}
+
+		var firstErr error
+		for dir, ids := range byProject {
+			var missing []string
+			for _, id := range ids {
+				if _, ok := statuses[id]; !ok {
+					missing = append(missing, id)
+				}
+			}
+			if len(missing) == 0 {
+				continue
+			}
+			found, err := bead.Statuses(dir, missing)
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			for id, status := range found {
+				statuses[id] = status
+			}
+		}
+		if len(statuses) == 0 && firstErr != nil {
+			return beadStatusesMsg{err: firstErr}
+		}
+		return beadStatusesMsg{statuses: statuses}
 	}
 }
 
@@ -705,8 +1501,12 @@ func (m ModelV2) View() string {
 		mainContent = m.clusterView.View()
 	case ViewReview:
 		mainContent = m.reviewView.View()
+	case ViewClassify:
+		mainContent = m.classifyView.View()
 	case ViewCapture:
 		mainContent = m.captureView.View()
+	case ViewEntity:
+		mainContent = m.entityView.View()
 	default:
 		mainContent = m.renderListView(contentHeight)
 	}
@@ -779,6 +1579,9 @@ func (m ModelV2) renderHeaderV2() string {
 	}
 
 	beatCount := SubtitleStyle.Render(fmt.Sprintf("%d beats", len(m.list.Items())))
+	if n := len(m.newBeats); n > 0 {
+		beatCount += " " + StatusBarStyle.Render(fmt.Sprintf(" %d new %s ", n, pluralBeats(n)))
+	}
 
 	viewIndicator := ""
 	switch m.viewMode {
@@ -788,6 +1591,10 @@ func (m ModelV2) renderHeaderV2() string {
 		viewIndicator = StatusBarStyle.Render(" CLUSTERS ")
 	case ViewReview:
 		viewIndicator = StatusBarStyle.Render(" REVIEW ")
+	case ViewClassify:
+		viewIndicator = StatusBarStyle.Render(" CLASSIFY ")
+	case ViewEntity:
+		viewIndicator = StatusBarStyle.Render(" ENTITY ")
 	}
 
 	searchView := m.search.View()
@@ -839,24 +1646,34 @@ GLOBAL                        NAVIGATION
   Esc     Cancel/back           Enter   Select/expand
   /       Search                Tab     Cycle focus
   r       Refresh               [/]     Chain prev/next
+                                n/N     Next/prev mention
 
 VIEWS                         FILTERING
-  t       Timeline              1-7     Channel filter
+  t       Timeline              1-9     Channel filter
   C       Clusters              !       Clear filters
   S       Stale review          R       Sort by ripeness
-  f       Facet sidebar         E       Entity search
+  L       Classification review B       Bead filter
+  f       Facet sidebar         E       Focus entities
   e       Entity sidebar
 
+ENTITIES (focused with E)
+  m       Merge (mark, then target) T       Change type
+  P       Promote to concept      x       Hide
+  s       Split merged names      Enter   Filter by entity
+  o       Entity profile (also from a mention chosen with n/N)
+
 ACTIONS                       PROJECT
   y       Copy beat ID          p       Cycle projects
   Y       Copy content          a       All projects
-  b       Create bead
+  b       Create bead (epic on a cluster)
+  ^b      Epic bead from the beat's chain
   c       Add to chain
+  v/V     Set channel/source
 
 LAYOUT: Compact(<60) Normal(100) Wide(140) UltraWide(180)
 Sidebars auto-show/hide based on terminal width.
 
-Ripeness: ⚪Fresh 🟡Maturing 🟢Ripe 🔴Overripe
+Ripeness: ⚪Fresh 🟡Maturing 🟢Ripe 🔴Overripe 🟤Compost ✅Resolved
 
                     Press any key to close`
 	return lipgloss.NewStyle().Padding(1, 2).Render(helpText)
@@ -925,10 +1742,11 @@ func EnrichedBeatsToItems(beats []model.EnrichedBeat, beatToProject map[string]s
 
 type EnrichedBeatDelegate struct {
 	width int
+	tiers model.RipenessThresholds
 }
 
 func NewEnrichedBeatDelegate() EnrichedBeatDelegate {
-	return EnrichedBeatDelegate{width: 80}
+	return EnrichedBeatDelegate{width: 80, tiers: model.DefaultRipenessThresholds}
 }
 
 func (d EnrichedBeatDelegate) SetWidth(w int) EnrichedBeatDelegate {
@@ -936,6 +1754,11 @@ func (d EnrichedBeatDelegate) SetWidth(w int) EnrichedBeatDelegate {
 	return d
 }
 
+func (d EnrichedBeatDelegate) SetTiers(t model.RipenessThresholds) EnrichedBeatDelegate {
+	d.tiers = t
+	return d
+}
+
 func (d EnrichedBeatDelegate) Height() int {
 	return 2
 }
@@ -957,7 +1780,10 @@ func (d EnrichedBeatDelegate) Render(w io.Writer, m list.Model, index int, item
 	beat := bi.beat
 	isSelected := index == m.Index()
 
-	ripenessEmoji := model.RipenessEmoji(beat.RipenessScore)
+	ripenessEmoji := d.tiers.Emoji(beat.RipenessScore)
+	if beat.RipenessState != "" {
+		ripenessEmoji = beat.RipenessState.Emoji()
+	}
 	channelStr := beat.Taxonomy.Channel.String()
 	if len(channelStr) > 12 {
 		channelStr = channelStr[:12]
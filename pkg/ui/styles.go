@@ -1,6 +1,10 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/bierlingm/beats_viewer/pkg/model"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 var (
 	subtle    = lipgloss.AdaptiveColor{Light: "#D9DCCF", Dark: "#383838"}
@@ -69,8 +73,34 @@ var (
 				Foreground(lipgloss.Color("#FAFAFA")).
 				Background(highlight).
 				Padding(0, 1)
+
+	ActionPhraseStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.AdaptiveColor{Light: "#B8860B", Dark: "#FFD75F"})
+
+	RipenessBarStyle = lipgloss.NewStyle().
+				Foreground(special)
+
+	RipenessBarEmptyStyle = lipgloss.NewStyle().
+				Foreground(subtle)
+
+	CurrentMentionStyle = lipgloss.NewStyle().
+				Reverse(true)
 )
 
+var entityTypeColors = map[model.EntityType]lipgloss.AdaptiveColor{
+	model.EntityPerson:       {Light: "#D7005F", Dark: "#FF5FAF"},
+	model.EntityTool:         {Light: "#0087AF", Dark: "#5FD7FF"},
+	model.EntityConcept:      {Light: "#5F8700", Dark: "#AFD75F"},
+	model.EntityProject:      {Light: "#AF5F00", Dark: "#FFAF5F"},
+	model.EntityOrganization: {Light: "#5F5FAF", Dark: "#AFAFFF"},
+}
+
+// EntityStyle returns the highlight style for an entity type
+func EntityStyle(t model.EntityType) lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(entityTypeColors[t]).Underline(true)
+}
+
 func Truncate(s string, maxLen int) string {
 	if len(s) <= maxLen {
 		return s
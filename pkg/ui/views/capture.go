@@ -28,11 +28,13 @@ type CaptureView struct {
 	width    int
 	height   int
 
-	channel      model.Channel
-	source       model.Source
-	focusField   int // 0=channel, 1=source, 2=textarea
-	submitted    bool
-	cancelled    bool
+	channel    model.Channel
+	source     model.Source
+	channels   []model.Channel // choices, from the taxonomy definition
+	sources    []model.Source
+	focusField int // 0=channel, 1=source, 2=textarea
+	submitted  bool
+	cancelled  bool
 }
 
 func NewCaptureView(width, height int) *CaptureView {
@@ -49,6 +51,8 @@ func NewCaptureView(width, height int) *CaptureView {
 		height:     height,
 		channel:    model.ChannelReflection,
 		source:     model.SourceInternal,
+		channels:   model.AllChannels(),
+		sources:    model.AllSources(),
 		focusField: 2,
 	}
 }
@@ -59,6 +63,16 @@ func (cv *CaptureView) SetSize(width, height int) {
 	cv.textarea.SetWidth(width - 6)
 }
 
+// SetOptions sets the channels and sources to choose from
+func (cv *CaptureView) SetOptions(channels []model.Channel, sources []model.Source) {
+	if len(channels) > 0 {
+		cv.channels = channels
+	}
+	if len(sources) > 0 {
+		cv.sources = sources
+	}
+}
+
 func (cv *CaptureView) SetChannel(ch model.Channel) {
 	cv.channel = ch
 }
@@ -141,7 +155,7 @@ func (cv *CaptureView) Update(msg tea.Msg) tea.Cmd {
 }
 
 func (cv *CaptureView) cycleChannel() {
-	channels := model.AllChannels()
+	channels := cv.channels
 	for i, ch := range channels {
 		if ch == cv.channel {
 			cv.channel = channels[(i+1)%len(channels)]
@@ -152,7 +166,7 @@ func (cv *CaptureView) cycleChannel() {
 }
 
 func (cv *CaptureView) cycleChannelBack() {
-	channels := model.AllChannels()
+	channels := cv.channels
 	for i, ch := range channels {
 		if ch == cv.channel {
 			cv.channel = channels[(i+len(channels)-1)%len(channels)]
@@ -163,7 +177,7 @@ func (cv *CaptureView) cycleChannelBack() {
 }
 
 func (cv *CaptureView) cycleSource() {
-	sources := model.AllSources()
+	sources := cv.sources
 	for i, src := range sources {
 		if src == cv.source {
 			cv.source = sources[(i+1)%len(sources)]
@@ -174,7 +188,7 @@ func (cv *CaptureView) cycleSource() {
 }
 
 func (cv *CaptureView) cycleSourceBack() {
-	sources := model.AllSources()
+	sources := cv.sources
 	for i, src := range sources {
 		if src == cv.source {
 			cv.source = sources[(i+len(sources)-1)%len(sources)]
@@ -0,0 +1,300 @@
+package views
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bierlingm/beats_viewer/pkg/model"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	classifyChosenStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("#73F59F"))
+
+	classifyOptionStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.AdaptiveColor{Light: "#1a1a1a", Dark: "#dddddd"})
+)
+
+// ClassifyDecision is a channel and source confirmed for a beat in review
+type ClassifyDecision struct {
+	BeatID  string
+	Channel model.Channel
+	Source  model.Source
+}
+
+// ClassifyReviewView walks beats from the least confident classification up,
+// showing the suggested channel and source with their alternatives
+type ClassifyReviewView struct {
+	beats        []model.EnrichedBeat
+	channels     []model.Channel // all defined, in display order
+	sources      []model.Source
+	currentIndex int
+	completed    int
+	width        int
+	height       int
+
+	// The choice for the current beat, starting from the suggestion
+	channelOptions []model.Channel
+	sourceOptions  []model.Source
+	source         int
+	page           int // of channel options, classifyPageSize at a time
+}
+
+// classifyPageSize is how many channel options are shown, and numbered, at once
+const classifyPageSize = 9
+
+func NewClassifyReviewView(width, height int) *ClassifyReviewView {
+	return &ClassifyReviewView{width: width, height: height}
+}
+
+func (cv *ClassifyReviewView) SetSize(width, height int) {
+	cv.width = width
+	cv.height = height
+}
+
+// SetBeats starts a review of beats, in the order given, offering the
+// defined channels and sources
+func (cv *ClassifyReviewView) SetBeats(beats []model.EnrichedBeat, channels []model.Channel, sources []model.Source) {
+	cv.beats = beats
+	cv.channels = channels
+	cv.sources = sources
+	cv.currentIndex = 0
+	cv.completed = 0
+	cv.loadOptions()
+}
+
+func (cv *ClassifyReviewView) CurrentBeat() *model.EnrichedBeat {
+	if cv.currentIndex >= 0 && cv.currentIndex < len(cv.beats) {
+		return &cv.beats[cv.currentIndex]
+	}
+	return nil
+}
+
+func (cv *ClassifyReviewView) IsComplete() bool {
+	return cv.currentIndex >= len(cv.beats)
+}
+
+// Completed returns how many beats were accepted or corrected
+func (cv *ClassifyReviewView) Completed() int {
+	return cv.completed
+}
+
+// loadOptions ranks the current beat's alternatives: labels that scored,
+// best first, then the rest in definition order
+func (cv *ClassifyReviewView) loadOptions() {
+	cv.channelOptions, cv.sourceOptions, cv.source, cv.page = nil, nil, 0, 0
+	beat := cv.CurrentBeat()
+	if beat == nil {
+		return
+	}
+	var channelNames, sourceNames []string
+	for _, ch := range cv.channels {
+		channelNames = append(channelNames, string(ch))
+	}
+	for _, src := range cv.sources {
+		sourceNames = append(sourceNames, string(src))
+	}
+	for _, name := range rankOptions(string(beat.Taxonomy.Channel), beat.Taxonomy.Channels, channelNames) {
+		cv.channelOptions = append(cv.channelOptions, model.Channel(name))
+	}
+	for _, name := range rankOptions(string(beat.Taxonomy.Source), beat.Taxonomy.Sources, sourceNames) {
+		cv.sourceOptions = append(cv.sourceOptions, model.Source(name))
+	}
+}
+
+// rankOptions lists the suggestion first, then the other scored labels in
+// rank order, then the remaining names
+func rankOptions(suggested string, scores []model.LabelScore, names []string) []string {
+	seen := make(map[string]bool)
+	var options []string
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			options = append(options, name)
+		}
+	}
+	add(suggested)
+	for _, s := range scores {
+		add(s.Name)
+	}
+	for _, name := range names {
+		add(name)
+	}
+	return options
+}
+
+// Update handles a key, returning the decision made for the current beat,
+// if any. Enter or a accepts the suggestion, 1-9 picks a channel on the
+// page shown, c/C pages through the channels, s/S cycles the source first
+// and n or → skips.
+func (cv *ClassifyReviewView) Update(msg tea.Msg) (*ClassifyDecision, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || cv.IsComplete() {
+		return nil, nil
+	}
+
+	switch key := keyMsg.String(); key {
+	case "enter", "a":
+		if len(cv.channelOptions) > 0 {
+			return cv.decide(cv.channelOptions[0]), nil
+		}
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		if n := cv.page*classifyPageSize + int(key[0]-'1'); n < len(cv.channelOptions) {
+			return cv.decide(cv.channelOptions[n]), nil
+		}
+	case "c":
+		cv.page = (cv.page + 1) % cv.pages()
+	case "C":
+		cv.page = (cv.page + cv.pages() - 1) % cv.pages()
+	case "s":
+		if len(cv.sourceOptions) > 0 {
+			cv.source = (cv.source + 1) % len(cv.sourceOptions)
+		}
+	case "S":
+		if len(cv.sourceOptions) > 0 {
+			cv.source = (cv.source + len(cv.sourceOptions) - 1) % len(cv.sourceOptions)
+		}
+	case "right", "n":
+		cv.currentIndex++
+		cv.loadOptions()
+	}
+	return nil, nil
+}
+
+// pages returns how many pages the channel options fill, at least one
+func (cv *ClassifyReviewView) pages() int {
+	if len(cv.channelOptions) == 0 {
+		return 1
+	}
+	return (len(cv.channelOptions) + classifyPageSize - 1) / classifyPageSize
+}
+
+func (cv *ClassifyReviewView) decide(channel model.Channel) *ClassifyDecision {
+	decision := &ClassifyDecision{BeatID: cv.CurrentBeat().ID, Channel: channel}
+	if len(cv.sourceOptions) > 0 {
+		decision.Source = cv.sourceOptions[cv.source]
+	}
+	cv.completed++
+	cv.currentIndex++
+	cv.loadOptions()
+	return decision
+}
+
+func (cv *ClassifyReviewView) View() string {
+	if len(cv.beats) == 0 {
+		return lipgloss.NewStyle().
+			Width(cv.width).
+			Height(cv.height).
+			Padding(2).
+			Render("No automatic classifications to review! All caught up.")
+	}
+
+	if cv.IsComplete() {
+		return lipgloss.NewStyle().
+			Width(cv.width).
+			Height(cv.height).
+			Padding(2).
+			Render(fmt.Sprintf("Review complete! Classified %d beats.", cv.completed))
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(reviewTitleStyle.Render(
+		fmt.Sprintf("Classification Review (%d beats, least confident first)", len(cv.beats))))
+	sb.WriteString("\n\n")
+
+	beat := cv.CurrentBeat()
+	header := fmt.Sprintf("%s (%s, confidence %.0f%%)", beat.ID, beat.ImpetusLabel(), beat.Taxonomy.Confidence*100)
+	sb.WriteString(lipgloss.NewStyle().Bold(true).Render(header))
+	sb.WriteString("\n\n")
+
+	contentPreview := beat.Content
+	maxContent := cv.height - 26
+	if maxContent < 50 {
+		maxContent = 50
+	}
+	if len(contentPreview) > maxContent {
+		contentPreview = contentPreview[:maxContent] + "..."
+	}
+	sb.WriteString(reviewBeatStyle.Width(cv.width - 4).Render(contentPreview))
+	sb.WriteString("\n\n")
+
+	sb.WriteString("Channel:")
+	if cv.pages() > 1 {
+		sb.WriteString(reviewProgressStyle.Render(fmt.Sprintf("  (page %d/%d, c/C for more)", cv.page+1, cv.pages())))
+	}
+	sb.WriteString("\n")
+	start := cv.page * classifyPageSize
+	end := start + classifyPageSize
+	if end > len(cv.channelOptions) {
+		end = len(cv.channelOptions)
+	}
+	for i := start; i < end; i++ {
+		ch := cv.channelOptions[i]
+		option := fmt.Sprintf("  [%d] %s%s", i-start+1, ch, optionShare(beat.Taxonomy.Channels, string(ch)))
+		if i == 0 {
+			sb.WriteString(classifyChosenStyle.Render(option) + "\n")
+		} else {
+			sb.WriteString(classifyOptionStyle.Render(option) + "\n")
+		}
+	}
+
+	if len(cv.sourceOptions) > 0 {
+		src := cv.sourceOptions[cv.source]
+		sb.WriteString(fmt.Sprintf("Source:  %s%s",
+			classifyChosenStyle.Render(src.String()), optionShare(beat.Taxonomy.Sources, string(src))))
+		sb.WriteString(reviewProgressStyle.Render(fmt.Sprintf("  (%d/%d, s/S to change)", cv.source+1, len(cv.sourceOptions))))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(reviewActionStyle.Render("  [a/Enter] Accept") + " - Confirm the channel and source shown\n")
+	sb.WriteString(reviewActionStyle.Render("  [1-9] Fix") + " - Use another channel\n")
+	if cv.pages() > 1 {
+		sb.WriteString(reviewActionStyle.Render("  [c/C] More") + " - Show the next or previous channels\n")
+	}
+	sb.WriteString("\n")
+
+	progress := reviewProgressStyle.Render(
+		fmt.Sprintf("Progress: %d/%d    Skip: →/n    Quit: q", cv.completed, len(cv.beats)))
+	sb.WriteString(progress)
+
+	return lipgloss.NewStyle().
+		Width(cv.width).
+		Height(cv.height).
+		Render(sb.String())
+}
+
+// optionShare formats a label's share of the scores, or nothing if it did
+// not score
+func optionShare(scores []model.LabelScore, name string) string {
+	for _, s := range scores {
+		if s.Name == name {
+			return fmt.Sprintf(" %.0f%%", s.Score*100)
+		}
+	}
+	return ""
+}
+
+// FindUnsureBeats returns the automatically classified beats, least
+// confident first
+func FindUnsureBeats(beats []model.EnrichedBeat) []model.EnrichedBeat {
+	var unsure []model.EnrichedBeat
+	for _, b := range beats {
+		if !b.Taxonomy.Manual {
+			unsure = append(unsure, b)
+		}
+	}
+	sort.SliceStable(unsure, func(i, j int) bool {
+		if unsure[i].Taxonomy.Confidence != unsure[j].Taxonomy.Confidence {
+			return unsure[i].Taxonomy.Confidence < unsure[j].Taxonomy.Confidence
+		}
+		return unsure[i].ID < unsure[j].ID
+	})
+	return unsure
+}
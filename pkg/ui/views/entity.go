@@ -0,0 +1,234 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bierlingm/beats_viewer/pkg/entity"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	profileTitleStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("#7D56F4"))
+
+	profileLabelStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#626262"))
+
+	profileMentionStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("#73F59F"))
+
+	profileSelectedStyle = lipgloss.NewStyle().
+				Background(lipgloss.Color("#383838"))
+)
+
+// EntityProfileView is a page about one entity built from the beats that
+// mention it. The cursor moves over related entities and mentions.
+type EntityProfileView struct {
+	profile *entity.EntityProfile
+	width   int
+	height  int
+	cursor  int
+}
+
+func NewEntityProfileView(width, height int) *EntityProfileView {
+	return &EntityProfileView{width: width, height: height}
+}
+
+func (pv *EntityProfileView) SetSize(width, height int) {
+	pv.width = width
+	pv.height = height
+}
+
+// SetProfile shows a profile, moving the cursor to its first entry
+func (pv *EntityProfileView) SetProfile(profile *entity.EntityProfile) {
+	pv.profile = profile
+	pv.cursor = 0
+}
+
+// Profile returns the profile shown, or nil
+func (pv *EntityProfileView) Profile() *entity.EntityProfile {
+	return pv.profile
+}
+
+func (pv *EntityProfileView) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "j", "down":
+			if pv.cursor < pv.entryCount()-1 {
+				pv.cursor++
+			}
+		case "k", "up":
+			if pv.cursor > 0 {
+				pv.cursor--
+			}
+		case "g":
+			pv.cursor = 0
+		case "G":
+			pv.cursor = pv.entryCount() - 1
+		}
+	}
+	return nil
+}
+
+func (pv *EntityProfileView) entryCount() int {
+	if pv.profile == nil {
+		return 0
+	}
+	return len(pv.profile.Related) + len(pv.profile.Mentions)
+}
+
+// SelectedRelated returns the ID of the related entity under the cursor, if any
+func (pv *EntityProfileView) SelectedRelated() (string, bool) {
+	if pv.profile == nil || pv.cursor >= len(pv.profile.Related) {
+		return "", false
+	}
+	return pv.profile.Related[pv.cursor].TargetID, true
+}
+
+// SelectedBeatID returns the beat of the mention under the cursor, if any
+func (pv *EntityProfileView) SelectedBeatID() (string, bool) {
+	if pv.profile == nil {
+		return "", false
+	}
+	i := pv.cursor - len(pv.profile.Related)
+	if i < 0 || i >= len(pv.profile.Mentions) {
+		return "", false
+	}
+	return pv.profile.Mentions[i].BeatID, true
+}
+
+func (pv *EntityProfileView) View() string {
+	if pv.profile == nil {
+		return profileTitleStyle.Render("No entity selected")
+	}
+	p := pv.profile
+
+	var lines []string
+	cursorLine := 0
+	entry := 0
+	addEntry := func(line string) {
+		if entry == pv.cursor {
+			cursorLine = len(lines)
+			line = profileSelectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+		entry++
+	}
+
+	lines = append(lines, profileTitleStyle.Render(fmt.Sprintf("%s (%s)", p.Name, p.Type)))
+	lines = append(lines, profileLabelStyle.Render(fmt.Sprintf("%d beats · first seen %s · last seen %s",
+		len(p.BeatIDs), p.FirstSeen.Format("2006-01-02"), p.LastSeen.Format("2006-01-02"))))
+	if len(p.Surfaces) > 0 {
+		lines = append(lines, profileLabelStyle.Render("Also written: ")+strings.Join(p.Surfaces, ", "))
+	}
+	lines = append(lines, "")
+
+	lines = append(lines, profileLabelStyle.Render("Channels: ")+formatFacets(p.Channels))
+	lines = append(lines, profileLabelStyle.Render("Sources:  ")+formatFacets(p.Sources))
+	if len(p.Chains) > 0 {
+		lines = append(lines, profileLabelStyle.Render("Chains:   ")+formatFacets(p.Chains))
+	}
+	if len(p.Clusters) > 0 {
+		lines = append(lines, profileLabelStyle.Render("Clusters: ")+formatFacets(p.Clusters))
+	}
+	if len(p.LinkedBeads) > 0 {
+		lines = append(lines, profileLabelStyle.Render("Beads:    ")+strings.Join(p.LinkedBeads, ", "))
+	}
+
+	lines = append(lines, "", profileTitleStyle.Render(fmt.Sprintf("Related (%d)", len(p.Related))))
+	if len(p.Related) == 0 {
+		lines = append(lines, profileLabelStyle.Render("  none"))
+	}
+	for _, e := range p.Related {
+		addEntry(fmt.Sprintf("  %s %s", e.Target, profileLabelStyle.Render(fmt.Sprintf("(%d, %.2f)", e.Shared, e.NPMI))))
+	}
+
+	lines = append(lines, "", profileTitleStyle.Render(fmt.Sprintf("Mentions (%d)", len(p.Mentions))))
+	snippetWidth := pv.width - 16
+	for _, mc := range p.Mentions {
+		snippet := mc.Snippet[:mc.Start] +
+			profileMentionStyle.Render(mc.Snippet[mc.Start:mc.End]) +
+			mc.Snippet[mc.End:]
+		if snippetWidth > 10 && lipgloss.Width(snippet) > snippetWidth {
+			snippet = truncateSnippet(mc.Snippet, mc.Start, mc.End, snippetWidth)
+		}
+		addEntry(fmt.Sprintf("  %s %s", profileLabelStyle.Render(mc.CreatedAt.Format("2006-01-02")), snippet))
+	}
+
+	lines = append(lines, "", profileLabelStyle.Render("j/k Move  Enter Open beat or entity  Esc Back"))
+
+	// Keep the cursor in view
+	visibleHeight := pv.height - 1
+	if visibleHeight < 1 {
+		visibleHeight = 1
+	}
+	start := 0
+	if cursorLine >= visibleHeight-1 {
+		start = cursorLine - visibleHeight + 2
+	}
+	end := start + visibleHeight
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	return lipgloss.NewStyle().
+		Width(pv.width).
+		Height(pv.height).
+		Render(strings.Join(lines[start:end], "\n"))
+}
+
+// truncateSnippet shortens a snippet to width around its mention, keeping
+// the mention highlighted
+func truncateSnippet(snippet string, start, end, width int) string {
+	runes := []rune(snippet)
+	mStart := len([]rune(snippet[:start]))
+	mEnd := len([]rune(snippet[:end]))
+	if len(runes) <= width {
+		return snippet
+	}
+	from := mStart - (width-(mEnd-mStart))/2
+	if from < 0 {
+		from = 0
+	}
+	to := from + width
+	if to > len(runes) {
+		to = len(runes)
+		from = to - width
+		if from < 0 {
+			from = 0
+		}
+	}
+	if mEnd > to {
+		mEnd = to
+	}
+	if mStart < from {
+		mStart = from
+	}
+	prefix, suffix := "", ""
+	if from > 0 {
+		prefix = "…"
+	}
+	if to < len(runes) {
+		suffix = "…"
+	}
+	return prefix + string(runes[from:mStart]) +
+		profileMentionStyle.Render(string(runes[mStart:mEnd])) +
+		string(runes[mEnd:to]) + suffix
+}
+
+func formatFacets(facets []entity.FacetCount) string {
+	if len(facets) == 0 {
+		return profileLabelStyle.Render("none")
+	}
+	parts := make([]string, len(facets))
+	for i, f := range facets {
+		parts[i] = fmt.Sprintf("%s %d", f.Name, f.Count)
+	}
+	return strings.Join(parts, ", ")
+}
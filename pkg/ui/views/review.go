@@ -39,6 +39,24 @@ const (
 	ReviewSkip
 )
 
+// Outcome returns the persisted outcome for an action; skips have none
+func (a ReviewAction) Outcome() model.ReviewOutcome {
+	switch a {
+	case ReviewKeep:
+		return model.OutcomeKeep
+	case ReviewArchive:
+		return model.OutcomeArchive
+	case ReviewConvert:
+		return model.OutcomeConvert
+	case ReviewChain:
+		return model.OutcomeChain
+	case ReviewDelete:
+		return model.OutcomeDelete
+	default:
+		return ""
+	}
+}
+
 type StaleReviewView struct {
 	staleBeats   []model.EnrichedBeat
 	currentIndex int
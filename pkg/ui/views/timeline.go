@@ -1,6 +1,10 @@
 package views
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/bierlingm/beats_viewer/pkg/entity"
 	"github.com/bierlingm/beats_viewer/pkg/model"
 	"github.com/bierlingm/beats_viewer/pkg/timeline"
 
@@ -8,10 +12,27 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// trendsPanelWidth is the width of the rising/fading entities panel
+const trendsPanelWidth = 30
+
+// maxTrendEntities caps the rising and fading entities listed
+const maxTrendEntities = 5
+
+var (
+	trendTitleStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#7D56F4"))
+	trendRisingStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#73F59F"))
+	trendFadingStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6B6B"))
+	trendMutedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#626262"))
+)
+
 type TimelineView struct {
 	renderer *timeline.TimelineRenderer
 	data     *timeline.TimelineData
 	beats    []model.EnrichedBeat
+	entities []model.Entity
+	rising   []entity.EntityTrend
+	fading   []entity.EntityTrend
+	window   entity.TrendWindow
 	width    int
 	height   int
 }
@@ -27,13 +48,43 @@ func NewTimelineView(width, height int) *TimelineView {
 func (tv *TimelineView) SetSize(width, height int) {
 	tv.width = width
 	tv.height = height
+	if tv.showTrends() {
+		width -= trendsPanelWidth
+	}
 	tv.renderer.SetSize(width, height)
 }
 
+// showTrends reports whether there is room for the trends panel
+func (tv *TimelineView) showTrends() bool {
+	return tv.width >= 2*trendsPanelWidth+10
+}
+
 func (tv *TimelineView) SetBeats(beats []model.EnrichedBeat) {
 	tv.beats = beats
 	tv.data = timeline.BuildTimeline(beats, timeline.ZoomMonth)
 	tv.renderer.SetData(tv.data)
+	tv.updateTrends()
+}
+
+// SetChannelColors sets the colors of the taxonomy's channels
+func (tv *TimelineView) SetChannelColors(colors map[model.Channel]string) {
+	tv.renderer.SetChannelColors(colors)
+}
+
+// SetEntities sets the entities whose trends are listed beside the timeline
+func (tv *TimelineView) SetEntities(entities []model.Entity) {
+	tv.entities = entities
+	tv.updateTrends()
+}
+
+func (tv *TimelineView) updateTrends() {
+	tv.rising, tv.fading = nil, nil
+	if tv.data == nil {
+		return
+	}
+	trends, window := entity.BuildTrends(tv.entities, tv.data, 1)
+	tv.window = window
+	tv.rising, tv.fading = entity.TopTrends(trends, maxTrendEntities)
 }
 
 func (tv *TimelineView) Update(msg tea.Msg) tea.Cmd {
@@ -48,6 +99,7 @@ func (tv *TimelineView) Update(msg tea.Msg) tea.Cmd {
 			newZoom := tv.renderer.CycleZoom()
 			tv.data = timeline.BuildTimeline(tv.beats, newZoom)
 			tv.renderer.SetData(tv.data)
+			tv.updateTrends()
 		case "c":
 			tv.renderer.ToggleColors()
 		}
@@ -63,8 +115,56 @@ func (tv *TimelineView) SelectedBeatIDs() []string {
 }
 
 func (tv *TimelineView) View() string {
+	content := tv.renderer.Render()
+	if tv.showTrends() {
+		content = lipgloss.JoinHorizontal(lipgloss.Top,
+			lipgloss.NewStyle().Width(tv.width-trendsPanelWidth).Render(content),
+			tv.renderTrends())
+	}
 	return lipgloss.NewStyle().
 		Width(tv.width).
 		Height(tv.height).
-		Render(tv.renderer.Render())
+		Render(content)
+}
+
+// renderTrends lists the entities rising and fading in the latest period at
+// the current zoom level
+func (tv *TimelineView) renderTrends() string {
+	var lines []string
+	period := "this " + strings.ToLower(tv.window.Zoom.String())
+	lines = append(lines, trendTitleStyle.Render("Entities "+period))
+
+	lines = append(lines, "", trendTitleStyle.Render("Rising"))
+	if len(tv.rising) == 0 {
+		lines = append(lines, trendMutedStyle.Render("  none"))
+	}
+	for _, t := range tv.rising {
+		change := fmt.Sprintf("+%d", t.Recent-t.Previous)
+		if t.Trend == entity.TrendNew {
+			change = "new"
+		}
+		lines = append(lines, trendRisingStyle.Render("▲ "+truncateName(t.Name))+" "+trendMutedStyle.Render(change))
+	}
+
+	lines = append(lines, "", trendTitleStyle.Render("Fading"))
+	if len(tv.fading) == 0 {
+		lines = append(lines, trendMutedStyle.Render("  none"))
+	}
+	for _, t := range tv.fading {
+		change := fmt.Sprintf("%d", t.Recent-t.Previous)
+		lines = append(lines, trendFadingStyle.Render("▼ "+truncateName(t.Name))+" "+trendMutedStyle.Render(change))
+	}
+
+	return lipgloss.NewStyle().
+		Width(trendsPanelWidth).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+}
+
+func truncateName(name string) string {
+	const limit = trendsPanelWidth - 10
+	if len([]rune(name)) > limit {
+		return string([]rune(name)[:limit-1]) + "…"
+	}
+	return name
 }
@@ -0,0 +1,57 @@
+package watch
+
+import (
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// notify watches the directories holding paths with fsnotify. Watching the
+// directory rather than the file keeps up when a file is replaced by a rename.
+func (w *Watcher) notify(paths []string) error {
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	dirs := make(map[string]bool)
+	for _, p := range paths {
+		dir := filepath.Dir(p)
+		if dirs[dir] {
+			continue
+		}
+		if err := fw.Add(dir); err != nil {
+			fw.Close()
+			return err
+		}
+		dirs[dir] = true
+	}
+
+	w.stop = func() { fw.Close() }
+	go w.readEvents(fw)
+	return nil
+}
+
+func (w *Watcher) readEvents(fw *fsnotify.Watcher) {
+	for {
+		select {
+		case event, ok := <-fw.Events:
+			if !ok {
+				return
+			}
+			if event.Has(fsnotify.Write) || event.Has(fsnotify.Create) || event.Has(fsnotify.Rename) {
+				w.changed(filepath.Clean(event.Name))
+			}
+		case err, ok := <-fw.Errors:
+			if !ok {
+				return
+			}
+			if err == fsnotify.ErrEventOverflow {
+				// Events were dropped; assume every file changed
+				for p := range w.paths {
+					w.changed(p)
+				}
+			}
+		}
+	}
+}
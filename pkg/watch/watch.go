@@ -0,0 +1,142 @@
+package watch
+
+import (
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// PollInterval is how often files are checked where file notifications aren't
+// available
+const PollInterval = 2 * time.Second
+
+// settle is how long a burst of writes to a file is gathered into one change
+const settle = 150 * time.Millisecond
+
+// Watcher reports changes to a set of files, through the platform's file
+// notifications, or by polling their size and modification time when those
+// fail
+type Watcher struct {
+	paths   map[string]bool
+	changes chan string
+	done    chan struct{}
+	once    sync.Once
+	stop    func()
+	polling bool
+}
+
+// New watches paths, which need not exist yet
+func New(paths []string) *Watcher {
+	w := &Watcher{
+		paths:   make(map[string]bool, len(paths)),
+		changes: make(chan string, 64),
+		done:    make(chan struct{}),
+	}
+	for _, p := range paths {
+		w.paths[p] = true
+	}
+	if err := w.notify(paths); err != nil {
+		w.polling = true
+		go w.poll(paths, PollInterval)
+	}
+	return w
+}
+
+// Polling reports whether the watcher fell back to polling
+func (w *Watcher) Polling() bool {
+	return w.polling
+}
+
+// Next blocks until watched files change and returns them, gathering writes
+// that follow within a short interval. It returns nil once the watcher is
+// closed.
+func (w *Watcher) Next() []string {
+	var first string
+	select {
+	case first = <-w.changes:
+	case <-w.done:
+		return nil
+	}
+
+	changed := map[string]bool{first: true}
+	timer := time.NewTimer(settle)
+	defer timer.Stop()
+	for {
+		select {
+		case p := <-w.changes:
+			changed[p] = true
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(settle)
+		case <-timer.C:
+			paths := make([]string, 0, len(changed))
+			for p := range changed {
+				paths = append(paths, p)
+			}
+			sort.Strings(paths)
+			return paths
+		case <-w.done:
+			return nil
+		}
+	}
+}
+
+// Close stops watching and wakes any caller blocked in Next
+func (w *Watcher) Close() {
+	w.once.Do(func() {
+		close(w.done)
+		if w.stop != nil {
+			w.stop()
+		}
+	})
+}
+
+// changed queues a change to a watched path
+func (w *Watcher) changed(path string) {
+	if !w.paths[path] {
+		return
+	}
+	select {
+	case w.changes <- path:
+	case <-w.done:
+	}
+}
+
+type fileState struct {
+	size    int64
+	modTime time.Time
+	exists  bool
+}
+
+func statFile(path string) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{size: info.Size(), modTime: info.ModTime(), exists: true}
+}
+
+func (w *Watcher) poll(paths []string, interval time.Duration) {
+	last := make(map[string]fileState, len(paths))
+	for _, p := range paths {
+		last[p] = statFile(p)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			for _, p := range paths {
+				if state := statFile(p); state != last[p] {
+					last[p] = state
+					w.changed(p)
+				}
+			}
+		case <-w.done:
+			return
+		}
+	}
+}